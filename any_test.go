@@ -0,0 +1,122 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"errors"
+	"github.com/smgrushb/conv/extend"
+	"github.com/smgrushb/conv/option"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/typepb"
+	"testing"
+)
+
+type anyFieldDTO struct {
+	Name   string
+	Number int32
+}
+
+type anyEvent struct {
+	ID      string
+	Payload *anypb.Any
+}
+
+type anyEventDTO struct {
+	ID      string
+	Payload anyFieldDTO
+}
+
+// 消息直接打包解包、解包为其他消息、Go结构体通过PackAny和UnpackAny往返
+func TestAnyRoundTrip(t *testing.T) {
+	field := &typepb.Field{Name: "id", Number: 3}
+	a, err := Convert[*anypb.Any](field, option.ConvProto())
+	if err != nil || a.GetTypeUrl() != "type.googleapis.com/google.protobuf.Field" {
+		t.Fatalf("pack: got %v, %v", a, err)
+	}
+	cases := []struct {
+		name string
+		conv func() (proto.Message, error)
+		want proto.Message
+	}{
+		{"same", func() (proto.Message, error) { return Convert[*typepb.Field](a, option.ConvProto()) }, field},
+		{"other", func() (proto.Message, error) { return Convert[*typepb.EnumValue](a, option.ConvProto()) }, &typepb.EnumValue{Name: "id", Number: 3}},
+	}
+	for _, c := range cases {
+		got, err := c.conv()
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !proto.Equal(got, c.want) {
+			t.Fatalf("%s: want %v, got %v", c.name, c.want, got)
+		}
+	}
+
+	dto := anyEventDTO{ID: "e1", Payload: anyFieldDTO{Name: "id", Number: 3}}
+	ev, err := Convert[*anyEvent](dto, option.PackAny[*typepb.Field, anyFieldDTO]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Convert[*typepb.Field](ev.Payload, option.ConvProto()); err != nil || !proto.Equal(got, field) {
+		t.Fatalf("packed payload: got %v, %v", got, err)
+	}
+	back, err := Convert[anyEventDTO](ev, option.UnpackAny[anyFieldDTO]())
+	if err != nil || back != dto {
+		t.Fatalf("unpack: want %+v, got %+v, %v", dto, back, err)
+	}
+	// Go结构体不会被Any2Message认领
+	if back, err = Convert[anyEventDTO](ev, option.ConvProto()); err != nil || back.Payload != (anyFieldDTO{}) {
+		t.Fatalf("without UnpackAny: got %+v, %v", back, err)
+	}
+}
+
+// type_url无法解析时开启ReportErrors报错
+func TestAnyUnknownType(t *testing.T) {
+	a := &anypb.Any{TypeUrl: "type.googleapis.com/unknown.Msg"}
+	if _, err := Convert[*typepb.Field](a, option.ConvProto(), option.ReportErrors()); !errors.Is(err, convextend.ErrAnyType) {
+		t.Fatalf("want %v, got %v", convextend.ErrAnyType, err)
+	}
+}
+
+type anyHookedDTO struct {
+	Name string
+}
+
+func (h *anyHookedDTO) AfterConv(any) error {
+	return errHook
+}
+
+type anyHookedEvent struct {
+	Payload anyHookedDTO
+}
+
+// 解包后转换中钩子返回的错误与外层一起返回，开启ReportErrors时路径接在Any字段之后
+func TestAnyNestedHookError(t *testing.T) {
+	ev := &anyEvent{ID: "e1"}
+	ev.Payload, _ = anypb.New(&typepb.Field{Name: "id"})
+	cases := []struct {
+		name string
+		opts []option.Option
+		path string
+	}{
+		{"quiet", []option.Option{option.UnpackAny[anyHookedDTO]()}, ""},
+		{"report", []option.Option{option.UnpackAny[anyHookedDTO](), option.ReportErrors()}, "anyHookedEvent.Payload"},
+	}
+	for _, c := range cases {
+		var dst anyHookedEvent
+		err := ConvertTo(ev, &dst, c.opts...)
+		if !errors.Is(err, errHook) {
+			t.Fatalf("%s: want %v, got %v", c.name, errHook, err)
+		}
+		var ce *ConvertError
+		if len(c.path) > 0 && (!errors.As(err, &ce) || ce.Path != c.path) {
+			t.Fatalf("%s: want path %s, got %v", c.name, c.path, err)
+		}
+		if dst.Payload.Name != "id" {
+			t.Fatalf("%s: want payload converted, got %+v", c.name, dst.Payload)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"github.com/smgrushb/conv/constant"
+	"github.com/smgrushb/conv/option"
+	"reflect"
+	"testing"
+)
+
+// 源长度与目标数组长度不一致时按ArrayLengthPolicy截断、置零或保留，Exact时不转换
+func TestArrayLengthPolicy(t *testing.T) {
+	cases := []struct {
+		name   string
+		src    any
+		policy constant.ArrayLengthPolicy
+		want   [3]int64
+	}{
+		{"shortZeroFill", []int{1}, constant.ArrayLengthPolicyZeroFill, [3]int64{1, 0, 0}},
+		{"shortKeep", []int{1}, constant.ArrayLengthPolicyKeep, [3]int64{1, 8, 9}},
+		{"shortExact", []int{1}, constant.ArrayLengthPolicyExact, [3]int64{7, 8, 9}},
+		{"longZeroFill", []int{1, 2, 3, 4}, constant.ArrayLengthPolicyZeroFill, [3]int64{1, 2, 3}},
+		{"longExact", []int{1, 2, 3, 4}, constant.ArrayLengthPolicyExact, [3]int64{7, 8, 9}},
+		{"equalExact", []int{1, 2, 3}, constant.ArrayLengthPolicyExact, [3]int64{1, 2, 3}},
+		{"sameElemShortKeep", []int64{1, 2}, constant.ArrayLengthPolicyKeep, [3]int64{1, 2, 9}},
+		{"sameElemShortZeroFill", []int64{1, 2}, constant.ArrayLengthPolicyZeroFill, [3]int64{1, 2, 0}},
+		{"arrayToArray", [2]int32{1, 2}, constant.ArrayLengthPolicyZeroFill, [3]int64{1, 2, 0}},
+		{"emptySlice", []int{}, constant.ArrayLengthPolicyKeep, [3]int64{7, 8, 9}},
+	}
+	for _, c := range cases {
+		dst := [3]int64{7, 8, 9}
+		if err := ConvertTo(c.src, &dst, option.ArrayLengthPolicy(c.policy)); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if dst != c.want {
+			t.Fatalf("%s: want %v, got %v", c.name, c.want, dst)
+		}
+	}
+}
+
+// 数组转切片按源长度，元素按目标类型转换
+func TestArrayToSlice(t *testing.T) {
+	cases := []struct {
+		name string
+		src  any
+		want []string
+	}{
+		{"ints", [2]int{1, 2}, []string{"1", "2"}},
+		{"empty", [0]int{}, []string{}},
+	}
+	for _, c := range cases {
+		got, err := Convert[[]string](c.src)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if len(got) != len(c.want) || len(got) > 0 && !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("%s: want %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+// 字节数组与字符串按原始字节互转，长度不一致时同样按ArrayLengthPolicy处理
+func TestByteArrayString(t *testing.T) {
+	if got, err := Convert[string]([4]byte{'a', 'b', 'c', 'd'}); err != nil || got != "abcd" {
+		t.Fatalf("to string: got %q, %v", got, err)
+	}
+	cases := []struct {
+		name   string
+		src    string
+		policy constant.ArrayLengthPolicy
+		want   [4]byte
+	}{
+		{"equal", "wxyz", constant.ArrayLengthPolicyExact, [4]byte{'w', 'x', 'y', 'z'}},
+		{"shortZeroFill", "ab", constant.ArrayLengthPolicyZeroFill, [4]byte{'a', 'b', 0, 0}},
+		{"shortKeep", "ab", constant.ArrayLengthPolicyKeep, [4]byte{'a', 'b', '3', '4'}},
+		{"longKeep", "abcdef", constant.ArrayLengthPolicyKeep, [4]byte{'a', 'b', 'c', 'd'}},
+		{"shortExact", "ab", constant.ArrayLengthPolicyExact, [4]byte{'1', '2', '3', '4'}},
+	}
+	for _, c := range cases {
+		dst := [4]byte{'1', '2', '3', '4'}
+		if err := ConvertTo(c.src, &dst, option.ArrayLengthPolicy(c.policy)); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if dst != c.want {
+			t.Fatalf("%s: want %q, got %q", c.name, c.want, dst)
+		}
+	}
+}
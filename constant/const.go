@@ -13,6 +13,8 @@ const (
 	MinUnixTimeString = internal.MinUnixTimeString
 	MinUnixStringTime = internal.MinUnixStringTime
 	MinUnixTimeAny    = internal.MinUnixTimeAny
+	MinUnixTimeNumber = internal.MinUnixTimeNumber
+	MinUnixNumberTime = internal.MinUnixNumberTime
 )
 
 const DefaultMinUnixScene = internal.DefaultMinUnixScene
@@ -30,3 +32,54 @@ const (
 	NilValuePolicyIgnore = internal.NilValuePolicyIgnore
 	NilValuePolicyZero   = internal.NilValuePolicyZero
 )
+
+type ArrayLengthPolicy = internal.ArrayLengthPolicy
+
+const (
+	ArrayLengthPolicyZeroFill = internal.ArrayLengthPolicyZeroFill
+	ArrayLengthPolicyKeep     = internal.ArrayLengthPolicyKeep
+	ArrayLengthPolicyExact    = internal.ArrayLengthPolicyExact
+)
+
+type MergeMode = internal.MergeMode
+
+const (
+	MergeModeOverwrite = internal.MergeModeOverwrite
+	MergeModeSkipZero  = internal.MergeModeSkipZero
+	MergeModeSkipNil   = internal.MergeModeSkipNil
+)
+
+type SliceStrategy = internal.SliceStrategy
+
+const (
+	SliceStrategyReplace = internal.SliceStrategyReplace
+	SliceStrategyAppend  = internal.SliceStrategyAppend
+	SliceStrategyIndex   = internal.SliceStrategyIndex
+	SliceStrategyKey     = internal.SliceStrategyKey
+)
+
+type CyclePolicy = internal.CyclePolicy
+
+const (
+	CyclePolicyNone  = internal.CyclePolicyNone
+	CyclePolicyReuse = internal.CyclePolicyReuse
+	CyclePolicyError = internal.CyclePolicyError
+)
+
+type FieldStatus = internal.FieldStatus
+
+const (
+	FieldStatusOK            = internal.FieldStatusOK
+	FieldStatusUnmatched     = internal.FieldStatusUnmatched
+	FieldStatusUnconvertible = internal.FieldStatusUnconvertible
+	FieldStatusBanned        = internal.FieldStatusBanned
+	FieldStatusFiltered      = internal.FieldStatusFiltered
+)
+
+type EnumUnknownPolicy = internal.EnumUnknownPolicy
+
+const (
+	EnumUnknownKeep   = internal.EnumUnknownKeep
+	EnumUnknownIgnore = internal.EnumUnknownIgnore
+	EnumUnknownError  = internal.EnumUnknownError
+)
@@ -48,27 +48,36 @@ func ConvertTo[To, From any](from From, to *To, opts ...option.Option) error {
 }
 
 func convertTo[To, From any](from From, to *To, phase int, opts ...option.Option) error {
+	c, src, err := converterFor(from, to, phase, opts...)
+	if err != nil {
+		return err
+	}
+	return c.Convert(to, src)
+}
+
+// converterFor 返回转换器以及传给Convert的源指针
+func converterFor[To, From any](from From, to *To, phase int, opts ...option.Option) (Converter, any, error) {
 	if gvalue.IsNil(to) {
-		return errors.New("[conv]destination should be a pointer")
+		return nil, nil, errors.New("[conv]destination should be a pointer")
 	}
 	c, err := newConverterOf(to, from, phase, opts...)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	// 如果是接口类型，判断接口的实现类型是否是指针，是则直接往下传，不是转成指针类型
 	if fromType := internal.ReflectType[From](); fromType.Kind() == reflect.Interface {
 		fromValue := reflect.ValueOf(from)
 		if fromValue.Kind() == reflect.Pointer {
-			return c.Convert(to, from)
+			return c, from, nil
 		}
 		if !fromValue.IsValid() {
-			return c.Convert(to, &from)
+			return c, &from, nil
 		}
 		ptr := reflect.New(fromValue.Type())
 		ptr.Elem().Set(fromValue)
-		return c.Convert(to, ptr.Interface())
+		return c, ptr.Interface(), nil
 	}
-	return c.Convert(to, &from)
+	return c, &from, nil
 }
 
 func Convert[To, From any](from From, opts ...option.Option) (t To, err error) {
@@ -88,6 +97,14 @@ func OstrichConvert[To, From any](from From, opts ...option.Option) To {
 	return t
 }
 
+// Clone 深拷贝，结果与源数据不共享内存，nil返回nil
+func Clone[T any](src T, opts ...option.Option) (T, error) {
+	if gvalue.IsNil(src) {
+		return src, nil
+	}
+	return Convert[T](src, append([]option.Option{option.DeepCopy()}, opts...)...)
+}
+
 func TwoPhaseConvertTo[To, Temp, From any](from From, to *To, opts ...option.Option) error {
 	temp := gvalue.Safe(gvalue.Zero[Temp]())
 	if reflect.TypeOf(temp) == nil && !internal.IsAnyType(temp) {
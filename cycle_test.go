@@ -0,0 +1,102 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"errors"
+	"github.com/smgrushb/conv/constant"
+	"github.com/smgrushb/conv/option"
+	"testing"
+)
+
+type cycleNode struct {
+	Name string
+	Next *cycleNode
+	Peer *cycleNode
+}
+
+type cycleNodeDTO struct {
+	Name string
+	Next *cycleNodeDTO
+	Peer *cycleNodeDTO
+}
+
+type cycleList struct {
+	Nodes []*cycleNode
+}
+
+type cycleListDTO struct {
+	Nodes []*cycleNodeDTO
+}
+
+// Reuse下目标与源的成环、共享结构一致，Error下成环的指针置为nil并报错
+func TestCyclePolicy(t *testing.T) {
+	self := &cycleNode{Name: "self"}
+	self.Next = self
+	a, b := &cycleNode{Name: "a"}, &cycleNode{Name: "b"}
+	a.Next, b.Next = b, a
+	leaf := &cycleNode{Name: "leaf"}
+	shared := &cycleNode{Name: "root", Next: leaf, Peer: leaf}
+	cases := []struct {
+		name   string
+		src    *cycleNode
+		policy constant.CyclePolicy
+		check  func(*cycleNodeDTO) bool
+		err    error
+	}{
+		{"selfReuse", self, constant.CyclePolicyReuse, func(d *cycleNodeDTO) bool { return d.Next == d }, nil},
+		{"pairReuse", a, constant.CyclePolicyReuse, func(d *cycleNodeDTO) bool {
+			return d.Next.Name == "b" && d.Next.Next == d
+		}, nil},
+		{"sharedReuse", shared, constant.CyclePolicyReuse, func(d *cycleNodeDTO) bool {
+			return d.Next != nil && d.Next == d.Peer && d.Next.Name == "leaf"
+		}, nil},
+		{"selfError", self, constant.CyclePolicyError, func(d *cycleNodeDTO) bool { return d.Name == "self" && d.Next == nil }, ErrCycle},
+		{"pairError", a, constant.CyclePolicyError, func(d *cycleNodeDTO) bool {
+			return d.Next.Name == "b" && d.Next.Next == nil
+		}, ErrCycle},
+		{"sharedError", shared, constant.CyclePolicyError, func(d *cycleNodeDTO) bool {
+			return d.Next != nil && d.Next == d.Peer
+		}, nil},
+	}
+	for _, c := range cases {
+		// 出错时Convert返回零值，使用ConvertTo检查已写入的部分
+		got := new(cycleNodeDTO)
+		err := ConvertTo(c.src, got, option.CyclePolicy(c.policy))
+		if c.err == nil && err != nil || c.err != nil && !errors.Is(err, c.err) {
+			t.Fatalf("%s: want error %v, got %v", c.name, c.err, err)
+		}
+		if !c.check(got) {
+			t.Fatalf("%s: unexpected result %+v", c.name, got)
+		}
+	}
+}
+
+// 切片元素引用同一指针时只转换一次
+func TestCycleSharedInSlice(t *testing.T) {
+	n := &cycleNode{Name: "n"}
+	n.Next = n
+	got, err := Convert[cycleListDTO](cycleList{Nodes: []*cycleNode{n, n}}, option.CyclePolicy(constant.CyclePolicyReuse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Nodes) != 2 || got.Nodes[0] != got.Nodes[1] || got.Nodes[0].Next != got.Nodes[0] {
+		t.Fatalf("unexpected result %+v", got.Nodes)
+	}
+}
+
+// 深拷贝同样按策略处理成环
+func TestCycleDeepCopy(t *testing.T) {
+	a, b := &cycleNode{Name: "a"}, &cycleNode{Name: "b"}
+	a.Next, b.Next, a.Peer = b, a, b
+	got, err := Convert[*cycleNode](a, option.DeepCopy(), option.CyclePolicy(constant.CyclePolicyReuse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == a || got.Next == b || got.Next.Next != got || got.Peer != got.Next {
+		t.Fatalf("unexpected copy %+v", got)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"github.com/smgrushb/conv/internal"
+)
+
+// ConvertError 开启option.ReportErrors后单个字段的转换错误
+type ConvertError = internal.ConvertError
+
+// ConvertErrors 开启option.ReportErrors后一次转换中收集到的全部错误
+type ConvertErrors = internal.ConvertErrors
+
+var (
+	ErrUnsupportedType = internal.ErrUnsupportedType
+	ErrOutOfRange      = internal.ErrOutOfRange
+	ErrCycle           = internal.ErrCycle
+	ErrFallthrough     = internal.ErrFallthrough
+	ErrInvalidTag      = internal.ErrInvalidTag
+)
@@ -0,0 +1,36 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"errors"
+	"github.com/smgrushb/conv/option"
+	"testing"
+)
+
+type errDst struct {
+	A int8
+	B uint8
+}
+
+// ConvertErrors自身实现Is/As，不依赖Go 1.20的Unwrap() []error
+func TestConvertErrorsIsAs(t *testing.T) {
+	_, err := Convert[errDst](map[string]any{"A": 300, "B": -1}, option.ReportErrors(), option.Strict())
+	var errs ConvertErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("want 2 errors, got %v", err)
+	}
+	if !errs.Is(ErrOutOfRange) {
+		t.Fatalf("Is: want %v in %v", ErrOutOfRange, errs)
+	}
+	if errs.Is(ErrCycle) {
+		t.Fatalf("Is: unexpected %v in %v", ErrCycle, errs)
+	}
+	var ce *ConvertError
+	if !errs.As(&ce) || ce.Path != "errDst.A" {
+		t.Fatalf("As: want errDst.A, got %v", ce)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"fmt"
+	"github.com/smgrushb/conv/internal"
+	"github.com/smgrushb/conv/internal/generics/gvalue"
+	"github.com/smgrushb/conv/option"
+	"reflect"
+)
+
+// Plan From转To的转换计划，String()输出为表格
+type Plan = internal.Plan
+
+// FieldPlan 目标字段的转换计划
+type FieldPlan = internal.FieldPlan
+
+// Explain 按配置构建From转To的转换器，返回每个目标字段对应的源字段、选中的转换器，
+// 以及未匹配、无法转换的字段和未使用的源字段，用于排查字段没有被转换的原因
+func Explain[To, From any](opts ...option.Option) (*Plan, error) {
+	dstTyp, srcTyp := internal.ReflectType[To](), internal.ReflectType[From]()
+	// 接口类型且不是any
+	if dstTyp.Kind() == reflect.Interface && !internal.IsAnyType[To]() {
+		return nil, fmt.Errorf("bad destination type:%s", gvalue.ReflectPathType[To]())
+	}
+	if srcTyp.Kind() == reflect.Interface && !internal.IsAnyType[From]() {
+		return nil, fmt.Errorf("bad source type:%s", gvalue.ReflectPathType[From]())
+	}
+	return internal.Explain(dstTyp, srcTyp, internal.GetOption(0, opts...)), nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package convextend
+
+import (
+	"errors"
+	"fmt"
+	"github.com/smgrushb/conv/internal"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+func init() {
+	ProtoConverter = append(ProtoConverter,
+		Message2Any(),
+		Any2Message(),
+	)
+}
+
+// ErrAnyType Any的type_url无法解析，或解析出的消息无法转换为目标类型
+var ErrAnyType = errors.New("[conv]unresolvable any type")
+
+var (
+	anyType          = internal.ReflectType[anypb.Any]()
+	protoMessageType = internal.ReflectType[proto.Message]()
+)
+
+func isProtoMessage(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ != anyType && reflect.PointerTo(typ).Implements(protoMessageType)
+}
+
+type message2Any struct{}
+
+// Message2Any proto消息打包为Any
+func Message2Any() internal.CustomConverterV2 {
+	return &message2Any{}
+}
+
+func (s *message2Any) Is(dstTyp, srcTyp reflect.Type) bool {
+	return dstTyp == anyType && isProtoMessage(srcTyp)
+}
+
+// Converter 需要按源类型构建，实际使用Build
+func (s *message2Any) Converter() func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+	return func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+		return false
+	}
+}
+
+func (s *message2Any) Build(dstTyp, srcTyp reflect.Type, option *internal.StructOption) func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+	return func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+		if err := anypb.MarshalFrom((*anypb.Any)(dPtr), reflect.NewAt(srcTyp, sPtr).Interface().(proto.Message), proto.MarshalOptions{}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+func (s *message2Any) Key() string {
+	return "[message2Any]"
+}
+
+type any2Message struct{}
+
+// Any2Message 按type_url从AnyResolver（默认protoregistry.GlobalTypes）解析消息类型后解包，
+// 目标是该消息类型时直接解包，否则解包后按字段转换为目标消息，Go结构体作为目标时使用UnpackAny
+func Any2Message() internal.CustomConverterV2 {
+	return &any2Message{}
+}
+
+func (s *any2Message) Is(dstTyp, srcTyp reflect.Type) bool {
+	return srcTyp == anyType && isProtoMessage(dstTyp)
+}
+
+// Converter 需要按目标类型构建，实际使用Build
+func (s *any2Message) Converter() func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+	return func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+		return false
+	}
+}
+
+func (s *any2Message) Build(dstTyp, srcTyp reflect.Type, option *internal.StructOption) func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+	return buildUnpack(dstTyp, option)
+}
+
+func (s *any2Message) Key() string {
+	return "[any2Message]"
+}
+
+type unpackAny[To any] struct {
+	dstTyp reflect.Type
+}
+
+// UnpackAny 解包Any后按字段转换为Go结构体To，如UnpackAny[EventDTO]()，目标为proto消息时无需指定
+func UnpackAny[To any]() internal.CustomConverterV2 {
+	return &unpackAny[To]{dstTyp: internal.ReflectType[To]()}
+}
+
+func (s *unpackAny[To]) Is(dstTyp, srcTyp reflect.Type) bool {
+	return srcTyp == anyType && dstTyp == s.dstTyp
+}
+
+// Converter 需要按配置构建，实际使用Build
+func (s *unpackAny[To]) Converter() func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+	return func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+		return false
+	}
+}
+
+func (s *unpackAny[To]) Build(dstTyp, srcTyp reflect.Type, option *internal.StructOption) func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+	return buildUnpack(dstTyp, option)
+}
+
+func (s *unpackAny[To]) Key() string {
+	return fmt.Sprintf("[unpackAny:%v]", s.dstTyp)
+}
+
+// buildUnpack 解包Any并转换为dstTyp，按消息类型缓存转换器，构建期间不能嵌套构建，首次遇到该消息类型时再构建，
+// 无法转换时不缓存，嵌套转换的错误原样返回，由外层合并
+func buildUnpack(dstTyp reflect.Type, option *internal.StructOption) func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+	resolver := getAnyResolver(option)
+	var converters sync.Map // reflect.Type => *internal.Converter
+	converterOf := func(msgTyp reflect.Type) *internal.Converter {
+		if c, ok := converters.Load(msgTyp); ok {
+			return c.(*internal.Converter)
+		}
+		c := internal.NewConverter(dstTyp, msgTyp, option)
+		if c != nil {
+			converters.Store(msgTyp, c)
+		}
+		return c
+	}
+	return func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+		a := (*anypb.Any)(sPtr)
+		if len(a.GetTypeUrl()) == 0 {
+			return false, nil
+		}
+		mt, err := resolver.FindMessageByURL(a.GetTypeUrl())
+		if err != nil {
+			return false, fmt.Errorf("%w: %s: %v", ErrAnyType, a.GetTypeUrl(), err)
+		}
+		msg := mt.New().Interface()
+		msgTyp := reflect.TypeOf(msg).Elem()
+		if msgTyp == dstTyp {
+			msg = reflect.NewAt(dstTyp, dPtr).Interface().(proto.Message)
+		}
+		if err = (proto.UnmarshalOptions{Resolver: unmarshalResolver(resolver)}).Unmarshal(a.GetValue(), msg); err != nil {
+			return false, err
+		}
+		if msgTyp == dstTyp {
+			return true, nil
+		}
+		c := converterOf(msgTyp)
+		if c == nil {
+			return false, fmt.Errorf("%w: %s => %v", ErrAnyType, mt.Descriptor().FullName(), dstTyp)
+		}
+		if err = c.Convert(reflect.NewAt(dstTyp, dPtr).Interface(), msg); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+type packAny[Msg proto.Message, From any] struct {
+	msgTyp reflect.Type
+}
+
+// PackAny From先按字段转换为消息Msg再打包为Any，Msg为生成代码的消息指针类型，如PackAny[*pb.Event, EventDTO]()
+func PackAny[Msg proto.Message, From any]() internal.CustomConverterV2 {
+	return &packAny[Msg, From]{msgTyp: internal.ReflectType[Msg]().Elem()}
+}
+
+func (s *packAny[Msg, From]) Is(dstTyp, srcTyp reflect.Type) bool {
+	return dstTyp == anyType && srcTyp == internal.ReflectType[From]()
+}
+
+// Converter 需要按配置构建，实际使用Build
+func (s *packAny[Msg, From]) Converter() func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+	return func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+		return false
+	}
+}
+
+func (s *packAny[Msg, From]) Build(dstTyp, srcTyp reflect.Type, option *internal.StructOption) func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+	// 构建转换器期间不能嵌套构建，首次转换时再构建，无法转换时不缓存
+	var cached atomic.Pointer[internal.Converter]
+	return func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+		c := cached.Load()
+		if c == nil {
+			if c = internal.NewConverter(s.msgTyp, srcTyp, option); c == nil {
+				return false, fmt.Errorf("%w: %v => %v", ErrAnyType, srcTyp, s.msgTyp)
+			}
+			cached.Store(c)
+		}
+		msg := reflect.New(s.msgTyp).Interface().(proto.Message)
+		if err := c.Convert(msg, reflect.NewAt(srcTyp, sPtr).Interface()); err != nil {
+			return false, err
+		}
+		if err := anypb.MarshalFrom((*anypb.Any)(dPtr), msg, proto.MarshalOptions{}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+func (s *packAny[Msg, From]) Key() string {
+	return fmt.Sprintf("[packAny:%v<-%v]", s.msgTyp, internal.ReflectType[From]())
+}
+
+func getAnyResolver(option *internal.StructOption) protoregistry.MessageTypeResolver {
+	if option == nil || option.AnyResolver == nil {
+		return protoregistry.GlobalTypes
+	}
+	return option.AnyResolver
+}
+
+// unmarshalResolver 解包时嵌套的Any、扩展字段也使用同一个解析器，不支持扩展时退回全局注册表
+func unmarshalResolver(resolver protoregistry.MessageTypeResolver) interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+} {
+	if r, ok := resolver.(interface {
+		protoregistry.MessageTypeResolver
+		protoregistry.ExtensionTypeResolver
+	}); ok {
+		return r
+	}
+	return messageResolver{resolver}
+}
+
+type messageResolver struct {
+	protoregistry.MessageTypeResolver
+}
+
+func (messageResolver) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	return protoregistry.GlobalTypes.FindExtensionByName(field)
+}
+
+func (messageResolver) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	return protoregistry.GlobalTypes.FindExtensionByNumber(message, field)
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package convextend
+
+import (
+	"errors"
+	"fmt"
+	"github.com/smgrushb/conv/internal"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unsafe"
+)
+
+func init() {
+	ProtoConverter = append(ProtoConverter,
+		Enum2String(),
+		String2Enum(),
+	)
+}
+
+// ErrUnknownEnum EnumUnknownError下枚举值或名字未定义
+var ErrUnknownEnum = errors.New("[conv]unknown enum value")
+
+var protoEnumType = internal.ReflectType[protoreflect.Enum]()
+
+func isProtoEnum(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Int32 && typ.Implements(protoEnumType)
+}
+
+func enumDescriptor(typ reflect.Type) protoreflect.EnumDescriptor {
+	return reflect.Zero(typ).Interface().(protoreflect.Enum).Descriptor()
+}
+
+// enumPrefixes 去掉的前缀，未指定时按枚举类型名推导
+func enumPrefixes(ed protoreflect.EnumDescriptor, option *internal.StructOption) []string {
+	if option != nil && len(option.EnumPrefixes) > 0 {
+		return option.EnumPrefixes
+	}
+	return []string{upperSnake(string(ed.Name())) + "_"}
+}
+
+func trimEnumPrefix(name string, prefixes []string) string {
+	for _, p := range prefixes {
+		// 去掉前缀后为空或以数字开头时不是合法的枚举值名，保留原名
+		if trimmed := strings.TrimPrefix(name, p); trimmed != name && len(trimmed) > 0 && !unicode.IsDigit(rune(trimmed[0])) {
+			return trimmed
+		}
+	}
+	return name
+}
+
+// upperSnake OrderStatus => ORDER_STATUS，HTTPCode => HTTP_CODE
+func upperSnake(s string) string {
+	rs := []rune(s)
+	var sb strings.Builder
+	for i, r := range rs {
+		if i > 0 && unicode.IsUpper(r) && (!unicode.IsUpper(rs[i-1]) || i+1 < len(rs) && unicode.IsLower(rs[i+1])) && rs[i-1] != '_' {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
+
+type enum2String struct{}
+
+// Enum2String proto枚举转为枚举值名，由EnumTrimPrefix、EnumUnknownPolicy控制
+func Enum2String() internal.CustomConverterV2 {
+	return &enum2String{}
+}
+
+func (s *enum2String) Is(dstTyp, srcTyp reflect.Type) bool {
+	return dstTyp.Kind() == reflect.String && isProtoEnum(srcTyp)
+}
+
+// Converter 需要按枚举类型构建，实际使用Build
+func (s *enum2String) Converter() func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+	return func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+		return false
+	}
+}
+
+func (s *enum2String) Build(dstTyp, srcTyp reflect.Type, option *internal.StructOption) func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+	ed := enumDescriptor(srcTyp)
+	var prefixes []string
+	if option != nil && option.EnumTrimPrefix {
+		prefixes = enumPrefixes(ed, option)
+	}
+	names := make(map[int32]string, ed.Values().Len())
+	for i := 0; i < ed.Values().Len(); i++ {
+		v := ed.Values().Get(i)
+		// 别名（allow_alias）使用第一个
+		if _, ok := names[int32(v.Number())]; !ok {
+			names[int32(v.Number())] = trimEnumPrefix(string(v.Name()), prefixes)
+		}
+	}
+	policy := getEnumUnknownPolicy(option)
+	return func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+		n := *(*int32)(sPtr)
+		name, ok := names[n]
+		if !ok {
+			switch policy {
+			case internal.EnumUnknownIgnore:
+				return false, nil
+			case internal.EnumUnknownError:
+				return false, fmt.Errorf("%w: %s(%d)", ErrUnknownEnum, ed.FullName(), n)
+			}
+			name = strconv.FormatInt(int64(n), 10)
+		}
+		*(*string)(dPtr) = name
+		return true, nil
+	}
+}
+
+func (s *enum2String) Key() string {
+	return "[enum2String]"
+}
+
+type string2Enum struct{}
+
+// String2Enum 枚举值名转为proto枚举，由EnumTrimPrefix、EnumCaseInsensitive、EnumUnknownPolicy控制，空字符串转为0
+func String2Enum() internal.CustomConverterV2 {
+	return &string2Enum{}
+}
+
+func (s *string2Enum) Is(dstTyp, srcTyp reflect.Type) bool {
+	return srcTyp.Kind() == reflect.String && isProtoEnum(dstTyp)
+}
+
+// Converter 需要按枚举类型构建，实际使用Build
+func (s *string2Enum) Converter() func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+	return func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+		return false
+	}
+}
+
+func (s *string2Enum) Build(dstTyp, srcTyp reflect.Type, option *internal.StructOption) func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+	ed := enumDescriptor(dstTyp)
+	prefixes := enumPrefixes(ed, option)
+	fold := option != nil && option.EnumCaseInsensitive
+	normalize := func(name string) string {
+		if fold {
+			return strings.ToUpper(name)
+		}
+		return name
+	}
+	numbers := make(map[string]int32, ed.Values().Len()*2)
+	// 完整的名字优先于去掉前缀后的名字
+	for i := 0; i < ed.Values().Len(); i++ {
+		v := ed.Values().Get(i)
+		if trimmed := trimEnumPrefix(string(v.Name()), prefixes); trimmed != string(v.Name()) {
+			numbers[normalize(trimmed)] = int32(v.Number())
+		}
+	}
+	for i := 0; i < ed.Values().Len(); i++ {
+		v := ed.Values().Get(i)
+		numbers[normalize(string(v.Name()))] = int32(v.Number())
+	}
+	policy := getEnumUnknownPolicy(option)
+	return func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+		name := *(*string)(sPtr)
+		if len(name) == 0 {
+			*(*int32)(dPtr) = 0
+			return true, nil
+		}
+		n, ok := numbers[normalize(name)]
+		if !ok {
+			switch policy {
+			case internal.EnumUnknownIgnore:
+				return false, nil
+			case internal.EnumUnknownError:
+				return false, fmt.Errorf("%w: %s(%q)", ErrUnknownEnum, ed.FullName(), name)
+			}
+			i, err := strconv.ParseInt(name, 10, 32)
+			if errors.Is(err, strconv.ErrRange) {
+				return false, fmt.Errorf("%w: %s(%q)", internal.ErrOutOfRange, ed.FullName(), name)
+			} else if err != nil {
+				return false, fmt.Errorf("%w: %s(%q)", ErrUnknownEnum, ed.FullName(), name)
+			}
+			n = int32(i)
+		}
+		*(*int32)(dPtr) = n
+		return true, nil
+	}
+}
+
+func (s *string2Enum) Key() string {
+	return "[string2Enum]"
+}
+
+func getEnumUnknownPolicy(option *internal.StructOption) internal.EnumUnknownPolicy {
+	if option == nil {
+		return internal.EnumUnknownKeep
+	}
+	return option.EnumUnknownPolicy
+}
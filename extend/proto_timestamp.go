@@ -39,8 +39,8 @@ func cvtTimePbTimestamp(as asTime, dPtr, sPtr unsafe.Pointer) {
 	*(*timestamppb.Timestamp)(dPtr) = *timestamppb.New(*as(sPtr))
 }
 
-func cvtPbTimestampTime(as asTime, dPtr, sPtr unsafe.Pointer) {
-	*as(dPtr) = (*timestamppb.Timestamp)(sPtr).AsTime().In(time.Local)
+func cvtPbTimestampTime(as asTime, loc *time.Location, dPtr, sPtr unsafe.Pointer) {
+	*as(dPtr) = (*timestamppb.Timestamp)(sPtr).AsTime().In(loc)
 }
 
 type time2Timestamp[T any] struct {
@@ -95,8 +95,16 @@ func (s *timestamp2Time[T]) Is(dstTyp, srcTyp reflect.Type) bool {
 }
 
 func (s *timestamp2Time[T]) Converter() func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+	return s.ConverterWithOption(nil)
+}
+
+func (s *timestamp2Time[T]) ConverterWithOption(option *internal.StructOption) func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
+	loc := internal.GetTimeLocation(option)
+	if loc == nil {
+		loc = time.Local
+	}
 	return func(dPtr unsafe.Pointer, sPtr unsafe.Pointer) bool {
-		cvtPbTimestampTime(s.wrapper.As, dPtr, sPtr)
+		cvtPbTimestampTime(s.wrapper.As, loc, dPtr, sPtr)
 		return true
 	}
 }
@@ -0,0 +1,35 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"fmt"
+	"github.com/smgrushb/conv/internal"
+	"github.com/smgrushb/conv/internal/generics/gvalue"
+	"github.com/smgrushb/conv/option"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"reflect"
+)
+
+// ConvertWithFieldMask 同Convert，同时返回转换中实际写入且有值（消息字段按Has判断）的目标字段组成的FieldMask，
+// 路径按目标字段名（proto字段名、tag或Go字段名），只包含叶子字段，与fieldmaskpb一致路径在repeated、map字段处截止，
+// 整体复制的字段（如同类型结构体）记录为该字段本身
+func ConvertWithFieldMask[To, From any](from From, opts ...option.Option) (t To, mask *fieldmaskpb.FieldMask, err error) {
+	res := gvalue.Safe(gvalue.Zero[To]())
+	if reflect.TypeOf(res) == nil && !internal.IsAnyType(res) {
+		err = fmt.Errorf("bad dst type:%s", gvalue.ReflectPathType[To]())
+		return
+	}
+	c, src, err := converterFor(from, &res, 0, opts...)
+	if err != nil {
+		return
+	}
+	paths, err := c.(*internal.Converter).ConvertMask(&res, src)
+	if err != nil {
+		return
+	}
+	return res, &fieldmaskpb.FieldMask{Paths: paths}, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"github.com/smgrushb/conv/option"
+	"google.golang.org/protobuf/types/known/apipb"
+	"reflect"
+	"testing"
+)
+
+type maskMethod struct {
+	Name           string
+	RequestTypeUrl string
+}
+
+type maskApi struct {
+	Name    string
+	Version string
+	Methods []maskMethod
+}
+
+// 只记录有值的字段，路径在repeated字段处截止
+func TestConvertWithFieldMask(t *testing.T) {
+	_, mask, err := ConvertWithFieldMask[*apipb.Api](maskApi{Name: "api", Methods: []maskMethod{{Name: "get"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"name", "methods"}; !reflect.DeepEqual(mask.GetPaths(), want) {
+		t.Fatalf("want %v, got %v", want, mask.GetPaths())
+	}
+	if !mask.IsValid(&apipb.Api{}) {
+		t.Fatalf("invalid mask %v", mask.GetPaths())
+	}
+}
+
+// 路径匹配时忽略大小写和下划线，没有下级路径的字段整体转换
+func TestFieldMaskPaths(t *testing.T) {
+	src := &apipb.Api{Name: "api", Version: "v1", Methods: []*apipb.Method{{Name: "get", RequestTypeUrl: "req"}}}
+	res, err := Convert[maskApi](src, option.FieldMaskPaths("version", "Methods.request_type_url"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (maskApi{Version: "v1", Methods: []maskMethod{{RequestTypeUrl: "req"}}}); !reflect.DeepEqual(res, want) {
+		t.Fatalf("want %+v, got %+v", want, res)
+	}
+	res, err = Convert[maskApi](src, option.FieldMaskPaths("methods"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (maskApi{Methods: []maskMethod{{Name: "get", RequestTypeUrl: "req"}}}); !reflect.DeepEqual(res, want) {
+		t.Fatalf("want %+v, got %+v", want, res)
+	}
+}
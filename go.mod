@@ -1,16 +1,16 @@
 module github.com/smgrushb/conv
 
-go 1.18
+go 1.21
 
 require (
-	github.com/bytedance/sonic v1.12.5
+	github.com/bytedance/sonic v1.15.4
 	google.golang.org/protobuf v1.35.2
 )
 
 require (
-	github.com/bytedance/sonic/loader v0.2.1 // indirect
-	github.com/cloudwego/base64x v0.1.4 // indirect
-	github.com/cloudwego/iasm v0.2.0 // indirect
+	github.com/bytedance/gopkg v0.1.3 // indirect
+	github.com/bytedance/sonic/loader v0.5.2 // indirect
+	github.com/cloudwego/base64x v0.1.6 // indirect
 	github.com/klauspost/cpuid/v2 v2.2.9 // indirect
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
 	golang.org/x/arch v0.12.0 // indirect
@@ -0,0 +1,22 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"github.com/smgrushb/conv/internal"
+)
+
+// BeforeConverter 目标结构体（指针接收者）实现，字段转换前调用，src为指向源值的指针
+type BeforeConverter = internal.BeforeConverter
+
+// AfterConverter 目标结构体（指针接收者）实现，字段转换完成后调用，src为指向源值的指针
+type AfterConverter = internal.AfterConverter
+
+// BeforeConverterTo 源结构体（指针接收者）实现，字段转换前调用，dst为指向目标值的指针
+type BeforeConverterTo = internal.BeforeConverterTo
+
+// AfterConverterTo 源结构体（指针接收者）实现，字段转换完成后调用，dst为指向目标值的指针
+type AfterConverterTo = internal.AfterConverterTo
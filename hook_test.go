@@ -0,0 +1,38 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"errors"
+	"testing"
+)
+
+var errHook = errors.New("hook failed")
+
+type hookedDst struct {
+	A int
+}
+
+func (h *hookedDst) AfterConv(any) error {
+	return errHook
+}
+
+type hookedWrap struct {
+	H hookedDst
+}
+
+// 经过any到达的结构体，钩子在运行时才构建，返回的错误仍需传递
+func TestHookErrorThroughAny(t *testing.T) {
+	if _, err := Convert[hookedDst](map[string]any{"A": 1}); !errors.Is(err, errHook) {
+		t.Fatalf("direct: want %v, got %v", errHook, err)
+	}
+	if _, err := Convert[hookedWrap](map[string]any{"H": map[string]any{"A": 1}}); !errors.Is(err, errHook) {
+		t.Fatalf("through any: want %v, got %v", errHook, err)
+	}
+	if _, err := Convert[[]hookedWrap]([]any{map[string]any{"H": map[string]any{"A": 1}}}); !errors.Is(err, errHook) {
+		t.Fatalf("slice through any: want %v, got %v", errHook, err)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"fmt"
+	"github.com/smgrushb/conv/internal"
+	"github.com/smgrushb/conv/internal/generics/gvalue"
+	"github.com/smgrushb/conv/option"
+	"reflect"
+)
+
+// ConverterInfo From转To选中的转换器，Kind为custom时Custom是自定义转换器的Key
+type ConverterInfo = internal.ConverterInfo
+
+// CustomConverterPriority 自定义转换器可选实现，多个转换器匹配同一类型对时优先级高的生效，未实现时为0
+type CustomConverterPriority = internal.CustomConverterPriority
+
+// Inspect 按配置构建From转To的转换器，返回选中的转换器，类型不支持转换时返回错误
+func Inspect[To, From any](opts ...option.Option) (ConverterInfo, error) {
+	dstTyp, srcTyp := internal.ReflectType[To](), internal.ReflectType[From]()
+	// 接口类型且不是any
+	if dstTyp.Kind() == reflect.Interface && !internal.IsAnyType[To]() {
+		return ConverterInfo{}, fmt.Errorf("bad destination type:%s", gvalue.ReflectPathType[To]())
+	}
+	if srcTyp.Kind() == reflect.Interface && !internal.IsAnyType[From]() {
+		return ConverterInfo{}, fmt.Errorf("bad source type:%s", gvalue.ReflectPathType[From]())
+	}
+	info, ok := internal.Inspect(dstTyp, srcTyp, internal.GetOption(0, opts...))
+	if !ok {
+		return ConverterInfo{}, fmt.Errorf("can't convert source type %s to destination type %s", srcTyp, dstTyp)
+	}
+	return info, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/smgrushb/conv/internal/generics/gslice"
 	"github.com/smgrushb/conv/internal/ptr"
 	"reflect"
+	"sync"
 	"unsafe"
 )
 
@@ -53,7 +54,7 @@ func newAnyConverter(typ *convertType, srcReferDeep ...int) converter {
 	return c
 }
 
-func (a *anyConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (a *anyConverter) convert(dPtr, sPtr unsafe.Pointer, _ *convState) bool {
 	if a.isTimeType {
 		t := a.asTime(sPtr)
 		if t.Unix() < *a.minUnix {
@@ -79,3 +80,48 @@ func (a *anyConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
 func (a *anyConverter) SetSrcReferDeep(deep int) {
 	a.sReferDeep = deep
 }
+
+// fromAnyConverter any转非any类型，运行时按实际类型查找转换器
+type fromAnyConverter struct {
+	*convertType
+	converters sync.Map // reflect.Type => *Converter
+}
+
+// newFromAnyConverter 实际类型的转换器运行时才构建，根转换器按此传入quiet状态接收其中钩子返回的错误
+func newFromAnyConverter(typ *convertType) converter {
+	return &fromAnyConverter{convertType: typ}
+}
+
+func (f *fromAnyConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	val := reflect.ValueOf(*(*any)(sPtr))
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return false
+		}
+		val = val.Elem()
+	}
+	if !val.IsValid() {
+		return false
+	}
+	c := f.converterOf(val.Type())
+	if c == nil {
+		return cs.fail(f.dstTyp, val.Type(), ErrUnsupportedType)
+	}
+	if cs != nil && cs.quiet && c.hooked {
+		// 实际类型的转换链路中有钩子时才创建状态
+		local := newConvState(f.option, f.dstTyp, true, false)
+		ok := c.convert(dPtr, PtrOfAny(val), local)
+		cs.errs = append(cs.errs, local.errs...)
+		return ok
+	}
+	return c.convert(dPtr, PtrOfAny(val), cs)
+}
+
+func (f *fromAnyConverter) converterOf(srcTyp reflect.Type) *Converter {
+	if c, ok := f.converters.Load(srcTyp); ok {
+		return c.(*Converter)
+	}
+	c := NewConverter(f.dstTyp, srcTyp, f.option)
+	f.converters.Store(srcTyp, c)
+	return c
+}
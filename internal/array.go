@@ -0,0 +1,145 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"github.com/smgrushb/conv/internal/ptr"
+	"github.com/smgrushb/conv/internal/unsafeheader"
+	"reflect"
+	"unsafe"
+)
+
+// arrayConverter 数组与数组、数组与切片互转
+type arrayConverter struct {
+	*convertType
+	*elemConverter
+	dElemSize uintptr
+	sElemSize uintptr
+	dLen      int // 目标是切片时为-1
+	sLen      int // 源是切片时为-1
+	policy    ArrayLengthPolicy
+	enable    bool // 兜底
+}
+
+func newArrayConverter(typ *convertType) converter {
+	c := &arrayConverter{
+		convertType: typ,
+		dElemSize:   typ.dstTyp.Elem().Size(),
+		sElemSize:   typ.srcTyp.Elem().Size(),
+		dLen:        -1,
+		sLen:        -1,
+		policy:      typ.option.ArrayLengthPolicy,
+	}
+	if typ.dstTyp.Kind() == reflect.Array {
+		c.dLen = typ.dstTyp.Len()
+	}
+	if typ.srcTyp.Kind() == reflect.Array {
+		c.sLen = typ.srcTyp.Len()
+	}
+	// 元素类型一致时直接按位复制
+	if c.enable = typ.srcTyp.Elem() == typ.dstTyp.Elem(); c.enable {
+		return c
+	}
+	key := typ.key()
+	// 先预注册进去，不然循环依赖下会循环解析
+	createdConverters[key] = &Converter{convertType: typ, converter: c}
+	if ec, ok := newElemConverter(typ.dstTyp.Elem(), typ.srcTyp.Elem(), typ.option); ok {
+		c.elemConverter = ec
+		c.enable = true
+		return c
+	}
+	// 把预注册的内容删了
+	delete(createdConverters, key)
+	return nil
+}
+
+func (a *arrayConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	if !a.enable {
+		return false
+	}
+	sData, sLen := sPtr, a.sLen
+	if sLen < 0 {
+		sSlice := (*unsafeheader.SliceHeader)(sPtr)
+		sData, sLen = sSlice.Data, sSlice.Len
+	}
+	dData, length := dPtr, a.dLen
+	if length < 0 {
+		length = sLen
+		dSlice := (*unsafeheader.SliceHeader)(dPtr)
+		dSlice.Len = length
+		if dSlice.Cap < length || dSlice.Data == nil {
+			dv := reflect.NewAt(a.dstTyp, dPtr).Elem()
+			newVal := reflect.MakeSlice(a.dstTyp, length, length)
+			dv.Set(newVal)
+		}
+		dData = dSlice.Data
+	} else if sLen != length {
+		if a.policy == ArrayLengthPolicyExact {
+			return false
+		}
+		if sLen < length {
+			if a.policy == ArrayLengthPolicyZeroFill {
+				dv := reflect.NewAt(a.dstTyp, dPtr).Elem()
+				zero := reflect.Zero(a.dstTyp.Elem())
+				for i := sLen; i < length; i++ {
+					dv.Index(i).Set(zero)
+				}
+			}
+			length = sLen
+		}
+	}
+	if a.elemConverter == nil {
+		ptr.Copy(dData, sData, uintptr(length)*a.sElemSize)
+		return true
+	}
+	for dOffset, sOffset, i := uintptr(0), uintptr(0), 0; i < length; i++ {
+		dElemPtr := unsafe.Pointer(uintptr(dData) + dOffset)
+		sElemPtr := unsafe.Pointer(uintptr(sData) + sOffset)
+		cs.pushIndex(i)
+		a.elemConverter.convert(dElemPtr, sElemPtr, cs)
+		cs.pop()
+		dOffset += a.dElemSize
+		sOffset += a.sElemSize
+	}
+	return true
+}
+
+// byteArrayConverter 字节数组与string互转，按原始字节复制
+type byteArrayConverter struct {
+	*convertType
+	length int
+	policy ArrayLengthPolicy
+}
+
+func newByteArrayConverter(typ *convertType) converter {
+	arrTyp := typ.srcTyp
+	if arrTyp.Kind() != reflect.Array {
+		arrTyp = typ.dstTyp
+	}
+	if arrTyp.Elem().Kind() != reflect.Uint8 || arrTyp.Elem().PkgPath() != "" {
+		return nil
+	}
+	return &byteArrayConverter{convertType: typ, length: arrTyp.Len(), policy: typ.option.ArrayLengthPolicy}
+}
+
+func (b *byteArrayConverter) convert(dPtr, sPtr unsafe.Pointer, _ *convState) bool {
+	if b.dstTyp.Kind() == reflect.String {
+		*(*string)(dPtr) = string(unsafe.Slice((*byte)(sPtr), b.length))
+		return true
+	}
+	s := *(*string)(sPtr)
+	if len(s) != b.length && b.policy == ArrayLengthPolicyExact {
+		return false
+	}
+	dst := unsafe.Slice((*byte)(dPtr), b.length)
+	n := copy(dst, s)
+	if b.policy == ArrayLengthPolicyZeroFill {
+		for i := n; i < b.length; i++ {
+			dst[i] = 0
+		}
+	}
+	return true
+}
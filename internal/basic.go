@@ -15,17 +15,33 @@ import (
 
 type basicConverter struct {
 	*convertType
-	cvtOp ptr.CvtOp
+	cvtOp  ptr.CvtOp
+	cvtOpE ptr.CvtOpE
 }
 
 func newBasicConverter(typ *convertType) converter {
+	checked := typ.option.ReportErrors || typ.option.Lenient
+	// 需要收集错误或宽松解析时any按实际类型转换，见fromAnyConverter
+	if checked && typ.srcTyp == ptr.AnyType {
+		return nil
+	}
 	if cvtOp := ptr.GetCvtOp(typ.srcTyp, typ.dstTyp, typ.option.StrBytesZeroCopy); cvtOp != nil {
-		return &basicConverter{convertType: typ, cvtOp: cvtOp}
+		c := &basicConverter{convertType: typ, cvtOp: cvtOp}
+		if checked {
+			c.cvtOpE = ptr.GetCvtOpE(typ.srcTyp, typ.dstTyp, typ.option.Strict, typ.option.Lenient)
+		}
+		return c
 	}
 	return nil
 }
 
-func (g *basicConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (g *basicConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	if g.cvtOpE != nil {
+		if err := g.cvtOpE(sPtr, dPtr); err != nil {
+			return cs.fail(g.dstTyp, g.srcTyp, err)
+		}
+		return true
+	}
 	g.cvtOp(sPtr, dPtr)
 	return true
 }
@@ -11,10 +11,12 @@ const (
 	MinUnixTimeString MinUnixSceneType = 1 << iota
 	MinUnixStringTime
 	MinUnixTimeAny
+	MinUnixTimeNumber
+	MinUnixNumberTime
 )
 
 const (
-	DefaultMinUnixScene = MinUnixTimeString | MinUnixStringTime
+	DefaultMinUnixScene = MinUnixTimeString | MinUnixStringTime | MinUnixTimeNumber | MinUnixNumberTime
 )
 
 // NilValuePolicy 定义了当转换 nil 指针到值类型时的行为。
@@ -24,3 +26,60 @@ const (
 	NilValuePolicyIgnore NilValuePolicy = iota // 忽略字段（跳过赋值）
 	NilValuePolicyZero                         // 使用源类型的零值（例如：nil *Struct -> Struct{}）
 )
+
+// ArrayLengthPolicy 定义了转换到数组时源长度与目标数组长度不一致的行为。
+type ArrayLengthPolicy int64
+
+const (
+	ArrayLengthPolicyZeroFill ArrayLengthPolicy = iota // 超出部分截断，不足部分目标元素置零值
+	ArrayLengthPolicyKeep                              // 超出部分截断，不足部分保留目标元素原值
+	ArrayLengthPolicyExact                             // 长度不一致时不转换
+)
+
+// MergeMode 定义了转换到已有目标时源字段的写入行为。
+type MergeMode int64
+
+const (
+	MergeModeOverwrite MergeMode = iota // 覆盖所有匹配的字段
+	MergeModeSkipZero                   // 源值为零值时不覆盖目标（nil指针、空字符串、0等）
+	MergeModeSkipNil                    // 仅源值为nil（指针、切片、map、接口等）时不覆盖目标
+)
+
+// SliceStrategy 定义了转换到已有的目标切片时的合并策略。
+type SliceStrategy int64
+
+const (
+	SliceStrategyReplace SliceStrategy = iota // 替换，目标长度与源一致
+	SliceStrategyAppend                       // 源元素转换后追加到目标末尾
+	SliceStrategyIndex                        // 按下标合并到目标已有元素上，超出部分追加
+	SliceStrategyKey                          // 按键字段合并到目标中键相同的元素上，未匹配的追加
+)
+
+// CyclePolicy 定义了源数据中指针成环（如 A->B->A）及多处引用同一指针时的行为。
+type CyclePolicy int64
+
+const (
+	CyclePolicyNone  CyclePolicy = iota // 不检测，数据成环时会无限递归
+	CyclePolicyReuse                    // 同一源指针只转换一次，成环和共享引用在目标中复现
+	CyclePolicyError                    // 共享引用同CyclePolicyReuse，成环时报错并将目标指针置为nil
+)
+
+// FieldStatus 定义了Explain中目标字段的转换状态。
+type FieldStatus string
+
+const (
+	FieldStatusOK            FieldStatus = "ok"            // 正常转换
+	FieldStatusUnmatched     FieldStatus = "unmatched"     // 没有同名的源字段或方法
+	FieldStatusUnconvertible FieldStatus = "unconvertible" // 源字段类型无法转换到目标字段类型
+	FieldStatusBanned        FieldStatus = "banned"        // 被Banned排除
+	FieldStatusFiltered      FieldStatus = "filtered"      // 不在WhiteList中
+)
+
+// EnumUnknownPolicy 定义了proto枚举与字符串互转时遇到未定义的值或名字的行为。
+type EnumUnknownPolicy int64
+
+const (
+	EnumUnknownKeep   EnumUnknownPolicy = iota // 枚举转字符串时输出数字，字符串是数字时按数字转为枚举，否则不写入
+	EnumUnknownIgnore                          // 不写入目标
+	EnumUnknownError                           // 不写入目标并报错
+)
@@ -0,0 +1,308 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"fmt"
+	"github.com/smgrushb/conv/internal/ptr"
+	"github.com/smgrushb/conv/internal/unsafeheader"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// copyFunc 同类型深拷贝，为nil时表示类型不含引用，按位复制即可
+type copyFunc func(dPtr, sPtr unsafe.Pointer, cs *convState)
+
+var (
+	copyFuncsMu sync.Mutex
+	copyFuncs   sync.Map // reflect.Type => copyFunc，any中的实际类型运行时才能确定，单独缓存
+
+	timeType     = ReflectType[time.Time]()
+	locationType = ReflectType[time.Location]()
+)
+
+// deepCopyConverter 同类型深拷贝，切片、map、指针、string都重新分配
+type deepCopyConverter struct {
+	*convertType
+	copy copyFunc
+}
+
+func newDeepCopyConverter(typ *convertType) converter {
+	if fn := getCopyFunc(typ.srcTyp); fn != nil {
+		return &deepCopyConverter{convertType: typ, copy: fn}
+	}
+	return nil
+}
+
+func (d *deepCopyConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	d.copy(dPtr, sPtr, cs)
+	return true
+}
+
+// wholeCopy 是否整体深拷贝，合并模式下的结构体、map以及非替换策略的切片需要合并到目标上，指定了FieldMask的结构体需要逐个字段转换
+func wholeCopy(option *StructOption, k reflect.Kind) bool {
+	switch k {
+	case reflect.Struct:
+		return option.wholeStruct()
+	case reflect.Map:
+		return option.MergeMode == MergeModeOverwrite
+	case reflect.Slice:
+		return option.SliceStrategy == SliceStrategyReplace
+	}
+	return true
+}
+
+func getCopyFunc(t reflect.Type) copyFunc {
+	if fn, ok := copyFuncs.Load(t); ok {
+		return fn.(copyFunc)
+	}
+	copyFuncsMu.Lock()
+	defer copyFuncsMu.Unlock()
+	if fn, ok := copyFuncs.Load(t); ok {
+		return fn.(copyFunc)
+	}
+	fn := newCopyFunc(t, make(map[reflect.Type]*copyFunc))
+	copyFuncs.Store(t, fn)
+	return fn
+}
+
+func newCopyFunc(t reflect.Type, building map[reflect.Type]*copyFunc) copyFunc {
+	if !hasPointers(t) {
+		return nil
+	}
+	// 递归类型，先占位
+	if fn, ok := building[t]; ok {
+		return func(dPtr, sPtr unsafe.Pointer, cs *convState) {
+			(*fn)(dPtr, sPtr, cs)
+		}
+	}
+	fn := new(copyFunc)
+	building[t] = fn
+	switch t.Kind() {
+	case reflect.String:
+		*fn = copyString
+	case reflect.Pointer:
+		*fn = newPtrCopyFunc(t, building)
+	case reflect.Slice:
+		*fn = newSliceCopyFunc(t, building)
+	case reflect.Map:
+		*fn = newMapCopyFunc(t, building)
+	case reflect.Interface:
+		*fn = newInterfaceCopyFunc(t)
+	case reflect.Array:
+		*fn = newArrayCopyFunc(t, building)
+	case reflect.Struct:
+		if isProtoStruct(t) {
+			*fn = newProtoCopyFunc(t)
+		} else {
+			*fn = newStructCopyFunc(t, building)
+		}
+	default:
+		// func、chan、unsafe.Pointer不拷贝，只复制引用
+		*fn = shallowCopyFunc(t)
+	}
+	return *fn
+}
+
+// hasPointers 是否包含指针，不含指针的类型可以直接按位复制
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface,
+		reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return true
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func shallowCopyFunc(t reflect.Type) copyFunc {
+	return func(dPtr, sPtr unsafe.Pointer, cs *convState) {
+		reflect.NewAt(t, dPtr).Elem().Set(reflect.NewAt(t, sPtr).Elem())
+	}
+}
+
+func copyString(dPtr, sPtr unsafe.Pointer, _ *convState) {
+	*(*string)(dPtr) = string([]byte(*(*string)(sPtr)))
+}
+
+// newProtoCopyFunc proto消息的值不能按字段复制内部状态，使用proto.Reset+proto.Merge
+func newProtoCopyFunc(t reflect.Type) copyFunc {
+	return func(dPtr, sPtr unsafe.Pointer, _ *convState) {
+		dm := protoMessageAt(t, dPtr)
+		proto.Reset(dm)
+		proto.Merge(dm, protoMessageAt(t, sPtr))
+	}
+}
+
+func newPtrCopyFunc(t reflect.Type, building map[reflect.Type]*copyFunc) copyFunc {
+	elemTyp := t.Elem()
+	// 时区全局共享，proto消息使用proto.Clone
+	if elemTyp == locationType {
+		return shallowCopyFunc(t)
+	}
+	if isProtoMessage(t) {
+		return func(dPtr, sPtr unsafe.Pointer, cs *convState) {
+			sv := reflect.NewAt(t, sPtr).Elem()
+			if sv.IsNil() {
+				*(*unsafe.Pointer)(dPtr) = nil
+				return
+			}
+			reflect.NewAt(t, dPtr).Elem().Set(reflect.ValueOf(proto.Clone(sv.Interface().(proto.Message))))
+		}
+	}
+	elemCopy, elemSize := newCopyFunc(elemTyp, building), elemTyp.Size()
+	return func(dPtr, sPtr unsafe.Pointer, cs *convState) {
+		sElemPtr := *(*unsafe.Pointer)(sPtr)
+		if sElemPtr == nil {
+			*(*unsafe.Pointer)(dPtr) = nil
+			return
+		}
+		var entry *visitEntry
+		if cs.tracking() {
+			var found bool
+			if entry, found = cs.visit(sElemPtr, elemTyp); found {
+				if entry.converting && cs.cycle == CyclePolicyError {
+					*(*unsafe.Pointer)(dPtr) = nil
+					cs.fail(elemTyp, elemTyp, ErrCycle)
+					return
+				}
+				*(*unsafe.Pointer)(dPtr) = entry.dPtr
+				return
+			}
+		}
+		dElemPtr := reflect.New(elemTyp).UnsafePointer()
+		if entry != nil {
+			entry.dPtr = dElemPtr
+		}
+		if elemCopy != nil {
+			elemCopy(dElemPtr, sElemPtr, cs)
+		} else {
+			ptr.Copy(dElemPtr, sElemPtr, elemSize)
+		}
+		if entry != nil {
+			entry.converting = false
+		}
+		*(*unsafe.Pointer)(dPtr) = dElemPtr
+	}
+}
+
+func newSliceCopyFunc(t reflect.Type, building map[reflect.Type]*copyFunc) copyFunc {
+	elemCopy, elemSize := newCopyFunc(t.Elem(), building), t.Elem().Size()
+	return func(dPtr, sPtr unsafe.Pointer, cs *convState) {
+		sSlice := (*unsafeheader.SliceHeader)(sPtr)
+		dv := reflect.NewAt(t, dPtr).Elem()
+		if sSlice.Data == nil {
+			dv.Set(reflect.Zero(t))
+			return
+		}
+		dv.Set(reflect.MakeSlice(t, sSlice.Len, sSlice.Len))
+		dData := (*unsafeheader.SliceHeader)(dPtr).Data
+		if elemCopy == nil {
+			ptr.Copy(dData, sSlice.Data, uintptr(sSlice.Len)*elemSize)
+			return
+		}
+		for offset, i := uintptr(0), 0; i < sSlice.Len; i++ {
+			cs.pushIndex(i)
+			elemCopy(unsafe.Pointer(uintptr(dData)+offset), unsafe.Pointer(uintptr(sSlice.Data)+offset), cs)
+			cs.pop()
+			offset += elemSize
+		}
+	}
+}
+
+func newArrayCopyFunc(t reflect.Type, building map[reflect.Type]*copyFunc) copyFunc {
+	elemCopy, elemSize, length := newCopyFunc(t.Elem(), building), t.Elem().Size(), t.Len()
+	return func(dPtr, sPtr unsafe.Pointer, cs *convState) {
+		for offset, i := uintptr(0), 0; i < length; i++ {
+			cs.pushIndex(i)
+			elemCopy(unsafe.Pointer(uintptr(dPtr)+offset), unsafe.Pointer(uintptr(sPtr)+offset), cs)
+			cs.pop()
+			offset += elemSize
+		}
+	}
+}
+
+func newMapCopyFunc(t reflect.Type, building map[reflect.Type]*copyFunc) copyFunc {
+	keyTyp, valTyp := t.Key(), t.Elem()
+	keyCopy, valCopy := newCopyFunc(keyTyp, building), newCopyFunc(valTyp, building)
+	return func(dPtr, sPtr unsafe.Pointer, cs *convState) {
+		sv, dv := reflect.NewAt(t, sPtr).Elem(), reflect.NewAt(t, dPtr).Elem()
+		if sv.IsNil() {
+			dv.Set(reflect.Zero(t))
+			return
+		}
+		m := reflect.MakeMapWithSize(t, sv.Len())
+		for iter := sv.MapRange(); iter.Next(); {
+			if cs != nil {
+				cs.pushKey(fmt.Sprint(iter.Key().Interface()))
+			}
+			m.SetMapIndex(copyValue(keyTyp, keyCopy, iter.Key(), cs), copyValue(valTyp, valCopy, iter.Value(), cs))
+			cs.pop()
+		}
+		dv.Set(m)
+	}
+}
+
+func newInterfaceCopyFunc(t reflect.Type) copyFunc {
+	return func(dPtr, sPtr unsafe.Pointer, cs *convState) {
+		sv, dv := reflect.NewAt(t, sPtr).Elem(), reflect.NewAt(t, dPtr).Elem()
+		if sv.IsNil() {
+			dv.Set(reflect.Zero(t))
+			return
+		}
+		elem := sv.Elem()
+		dv.Set(copyValue(elem.Type(), getCopyFunc(elem.Type()), elem, cs))
+	}
+}
+
+func newStructCopyFunc(t reflect.Type, building map[reflect.Type]*copyFunc) copyFunc {
+	// time.Time视为值类型
+	if t == timeType {
+		return shallowCopyFunc(t)
+	}
+	type fieldCopy struct {
+		name   string
+		offset uintptr
+		size   uintptr
+		copy   copyFunc
+	}
+	fields := make([]fieldCopy, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fields = append(fields, fieldCopy{name: f.Name, offset: f.Offset, size: f.Type.Size(), copy: newCopyFunc(f.Type, building)})
+	}
+	return func(dPtr, sPtr unsafe.Pointer, cs *convState) {
+		for _, f := range fields {
+			dFieldPtr, sFieldPtr := unsafe.Pointer(uintptr(dPtr)+f.offset), unsafe.Pointer(uintptr(sPtr)+f.offset)
+			if f.copy != nil {
+				cs.pushField(f.name)
+				f.copy(dFieldPtr, sFieldPtr, cs)
+				cs.pop()
+			} else {
+				ptr.Copy(dFieldPtr, sFieldPtr, f.size)
+			}
+		}
+	}
+}
+
+func copyValue(t reflect.Type, fn copyFunc, v reflect.Value, cs *convState) reflect.Value {
+	if fn == nil {
+		return v
+	}
+	res := reflect.New(t).Elem()
+	fn(unsafe.Pointer(res.UnsafeAddr()), PtrOfAny(v), cs)
+	return res
+}
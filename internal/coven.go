@@ -18,8 +18,11 @@ import (
 )
 
 var (
+	// createdConverters 构建过程中使用，包括预注册的转换器，读写都需要加锁
 	createdConvertersMu sync.Mutex
 	createdConverters   = make(map[convertTypeKey]*Converter)
+	// builtConverters 构建完成的转换器，读取无需加锁
+	builtConverters sync.Map // convertTypeKey => *Converter
 
 	zeroReflectValue reflect.Value
 )
@@ -46,40 +49,59 @@ func (c *convertType) key() convertTypeKey {
 }
 
 type converter interface {
-	convert(dPtr, sPtr unsafe.Pointer) bool
+	convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool
 }
 
 type Converter struct {
 	*convertType
 	converter
+	hooked  bool // 转换链路中有钩子，构建完成后由chainHooks得到
+	dynamic bool // 转换链路中有运行时才构建的部分（any转非any、Build构建的自定义转换器）
 }
 
 func (c *Converter) Convert(dst, src any) error {
+	_, err := c.convertValue(dst, src, false)
+	return err
+}
+
+// ConvertMask 同Convert，同时返回写入且有值的目标字段路径（按目标字段名，路径在切片、数组、map字段处截止），
+// 只记录叶子字段，整体复制或自定义转换的字段记录为该字段本身
+func (c *Converter) ConvertMask(dst, src any) ([]string, error) {
+	return c.convertValue(dst, src, true)
+}
+
+func (c *Converter) convertValue(dst, src any, masking bool) ([]string, error) {
 	if gvalue.IsNil(dst) || gvalue.IsNil(src) {
-		return nil
+		return nil, nil
 	}
 	dv := dereferencedValue(dst)
 	if dv == zeroReflectValue {
-		return nil
+		return nil, nil
 	}
 	if !dv.CanSet() {
-		return fmt.Errorf("[conv]destination should be a pointer. [actual:%v]", dv.Type())
+		return nil, fmt.Errorf("[conv]destination should be a pointer. [actual:%v]", dv.Type())
 	}
 	if dv.Type() != c.dstTyp {
-		return fmt.Errorf("[conv]invalid destination type. [expected:%v] [actual:%v]", c.dstTyp, dv.Type())
+		return nil, fmt.Errorf("[conv]invalid destination type. [expected:%v] [actual:%v]", c.dstTyp, dv.Type())
 	}
 	sv := dereferencedValue(src)
 	if sv == zeroReflectValue {
-		return nil
+		return nil, nil
 	}
 	if !sv.CanAddr() {
-		return fmt.Errorf("[conv]source should be a pointer. [actual:%v]", sv.Type())
+		return nil, fmt.Errorf("[conv]source should be a pointer. [actual:%v]", sv.Type())
 	}
 	if sv.Type() != c.srcTyp {
-		return fmt.Errorf("[conv]invalid source type. [expected:%v] [actual:%v]", c.srcTyp, sv.Type())
+		return nil, fmt.Errorf("[conv]invalid source type. [expected:%v] [actual:%v]", c.srcTyp, sv.Type())
 	}
-	c.converter.convert(unsafe.Pointer(dv.UnsafeAddr()), unsafe.Pointer(sv.UnsafeAddr()))
-	return nil
+	cs := newRootState(c.option, c.dstTyp, c.hooked, c.dynamic, masking)
+	dPtr, sPtr := unsafe.Pointer(dv.UnsafeAddr()), unsafe.Pointer(sv.UnsafeAddr())
+	cs.visitRoot(dPtr, sPtr, c.dstTyp)
+	c.converter.convert(dPtr, sPtr, cs)
+	if masking {
+		return cs.fieldMask(), cs.release()
+	}
+	return nil, cs.release()
 }
 
 func (c *Converter) isAnyConverter() (AnyConverter, bool) {
@@ -87,9 +109,24 @@ func (c *Converter) isAnyConverter() (AnyConverter, bool) {
 }
 
 func NewConverter(dstTyp, srcTyp reflect.Type, option *StructOption) *Converter {
+	dTyp, _ := dereferencedType(dstTyp)
+	sTyp, _ := dereferencedType(srcTyp)
+	key := (&convertType{dstTyp: dTyp, srcTyp: sTyp, option: option}).key()
+	if c, ok := builtConverters.Load(key); ok {
+		return c.(*Converter)
+	}
 	createdConvertersMu.Lock()
 	defer createdConvertersMu.Unlock()
-	return newConverter(dstTyp, srcTyp, option)
+	if c, ok := builtConverters.Load(key); ok {
+		return c.(*Converter)
+	}
+	// 构建完成后再对外可见，避免读到预注册但未构建完的转换器
+	c := newConverter(dstTyp, srcTyp, option)
+	if c != nil {
+		c.hooked, c.dynamic = chainHooks(c.converter)
+		builtConverters.Store(key, c)
+	}
+	return c
 }
 
 func newConverter(dstTyp, srcTyp reflect.Type, option *StructOption) *Converter {
@@ -101,20 +138,36 @@ func newConverter(dstTyp, srcTyp reflect.Type, option *StructOption) *Converter
 	if dc, ok := createdConverters[key]; ok {
 		return dc
 	}
-	var c converter
-	if option != nil {
-		for _, v := range option.CustomConv {
-			if v.Is(dstTyp, srcTyp) {
-				c = Custom(v.Converter())
-				break
-			}
+	c := newCustomConverter(cTyp)
+	if cc, ok := c.(*customConverter); ok && cc.version >= 3 {
+		// 自定义转换器放行时使用内置转换器
+		cc.fallback = newBuiltinConverter(cTyp, sReferDeep)
+		if dc, ok := createdConverters[key]; ok {
+			// 内置转换器预注册过，递归引用也先经过自定义转换器
+			dc.converter = c
 		}
-		for _, v := range option.CustomConvV2 {
-			if v.Is(dstTyp, srcTyp) {
-				c = CustomV2(v.Converter())
-				break
-			}
+	}
+	if c == nil {
+		c = newBuiltinConverter(cTyp, sReferDeep)
+	}
+	if c != nil {
+		// 可能预注册进去了，那就不要再注册
+		if _, ok := createdConverters[key]; !ok {
+			dc := &Converter{convertType: cTyp, converter: c}
+			createdConverters[key] = dc
+			return dc
 		}
+		return createdConverters[key]
+	}
+	return nil
+}
+
+// newBuiltinConverter 内置转换器
+func newBuiltinConverter(cTyp *convertType, sReferDeep int) converter {
+	dstTyp, srcTyp, option := cTyp.dstTyp, cTyp.srcTyp, cTyp.option
+	var c converter
+	if option != nil && option.DeepCopy && dstTyp == srcTyp && wholeCopy(option, srcTyp.Kind()) {
+		c = newDeepCopyConverter(cTyp)
 	}
 	if c == nil {
 		if option != nil && dstTyp.Kind() == reflect.String {
@@ -131,13 +184,23 @@ func newConverter(dstTyp, srcTyp reflect.Type, option *StructOption) *Converter
 	if c == nil {
 		if dstTyp == ptr.AnyType {
 			c = newAnyConverter(cTyp, sReferDeep)
+		} else if srcTyp == ptr.AnyType {
+			c = newFromAnyConverter(cTyp)
 		} else {
 			switch sk, dk := srcTyp.Kind(), dstTyp.Kind(); {
-			// todo: 数组转换
 			case sk == reflect.Struct && dk == reflect.Struct:
-				c = newStructConverter(cTyp)
+				if isProtoStruct(srcTyp) || isProtoStruct(dstTyp) {
+					c = newProtoConverter(cTyp)
+				} else {
+					c = newStructConverter(cTyp)
+				}
 			case sk == reflect.Slice && dk == reflect.Slice:
 				c = newSliceConverter(cTyp)
+			case sk == reflect.Array && (dk == reflect.Array || dk == reflect.Slice),
+				sk == reflect.Slice && dk == reflect.Array:
+				c = newArrayConverter(cTyp)
+			case sk == reflect.Array && dk == reflect.String, sk == reflect.String && dk == reflect.Array:
+				c = newByteArrayConverter(cTyp)
 			case sk == reflect.Map && dk == reflect.Map:
 				if dstTyp.Elem() == ptr.AnyType || dstTyp.Elem().Kind() != reflect.Interface {
 					c = newMapConverter(cTyp)
@@ -148,7 +211,7 @@ func newConverter(dstTyp, srcTyp reflect.Type, option *StructOption) *Converter
 				}
 			case sk == reflect.Map && dk == reflect.Struct:
 				if srcTyp.Key().Kind() == reflect.String && (srcTyp.Elem() == ptr.AnyType || srcTyp.Elem().Kind() == reflect.String) {
-					// TODO: map[string]any/map[string]string转结构体
+					c = newMapStructConverter(cTyp, srcTyp.Elem())
 				}
 			default:
 				c = newTimeConverter(cTyp)
@@ -163,14 +226,5 @@ func newConverter(dstTyp, srcTyp reflect.Type, option *StructOption) *Converter
 			}
 		}
 	}
-	if c != nil {
-		// 可能预注册进去了，那就不要再注册
-		if _, ok := createdConverters[key]; !ok {
-			dc := &Converter{convertType: cTyp, converter: c}
-			createdConverters[key] = dc
-			return dc
-		}
-		return createdConverters[key]
-	}
-	return nil
+	return c
 }
@@ -0,0 +1,82 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+type benchSrc struct {
+	ID    int64
+	Name  string
+	Tags  []string
+	Attrs map[string]string
+}
+
+type benchDst struct {
+	ID    string
+	Name  string
+	Tags  []string
+	Attrs map[string]string
+}
+
+var (
+	benchDstTyp = reflect.TypeOf(benchDst{})
+	benchSrcTyp = reflect.TypeOf(benchSrc{})
+	benchSeq    int64 // 生成不重复的配置
+)
+
+// mutexConverter 改为sync.Map之前的查找方式，每次都在createdConvertersMu内查找或构建
+func mutexConverter(dstTyp, srcTyp reflect.Type, option *StructOption) *Converter {
+	createdConvertersMu.Lock()
+	defer createdConvertersMu.Unlock()
+	return newConverter(dstTyp, srcTyp, option)
+}
+
+// benchmarkConvert 并发查找转换器并转换，optionOf返回每次转换使用的配置
+func benchmarkConvert(b *testing.B, lookup func(dstTyp, srcTyp reflect.Type, option *StructOption) *Converter, optionOf func() *StructOption) {
+	src := benchSrc{ID: 1, Name: "conv", Tags: []string{"a", "b"}, Attrs: map[string]string{"k": "v"}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var dst benchDst
+		for pb.Next() {
+			c := lookup(benchDstTyp, benchSrcTyp, optionOf())
+			if err := c.Convert(&dst, &src); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkConvertParallel 已构建的转换器读取无需加锁，首次构建仍需加锁，mutex为改动前的查找方式
+func BenchmarkConvertParallel(b *testing.B) {
+	lookups := []struct {
+		name   string
+		lookup func(dstTyp, srcTyp reflect.Type, option *StructOption) *Converter
+	}{
+		{"syncmap", NewConverter},
+		{"mutex", mutexConverter},
+	}
+	for _, l := range lookups {
+		b.Run("built/"+l.name, func(b *testing.B) {
+			option := defaultStructOption()
+			l.lookup(benchDstTyp, benchSrcTyp, option)
+			benchmarkConvert(b, l.lookup, func() *StructOption { return option })
+		})
+		b.Run("first/"+l.name, func(b *testing.B) {
+			// 每次使用不同的配置，转换器都需要新建
+			benchmarkConvert(b, l.lookup, func() *StructOption {
+				option := defaultStructOption()
+				option.TimeFormat = strconv.FormatInt(atomic.AddInt64(&benchSeq, 1), 10)
+				return option
+			})
+		})
+	}
+}
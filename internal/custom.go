@@ -6,13 +6,43 @@
 package internal
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
 	"unsafe"
 )
 
+// CustomConverterE 自定义转换器可选实现，需要返回错误时优先使用，返回false且无错误时不写入目标，
+// 返回ErrFallthrough时放弃本次转换，交由内置转换器处理
+type CustomConverterE interface {
+	ConverterE() func(dPtr, sPtr unsafe.Pointer) (bool, error)
+}
+
+// CustomConverterBuilder 自定义转换器可选实现，按实际的类型对和配置构建转换函数，优先于ConverterE等其他实现，
+// 返回的错误与内置转换器一致，开启ReportErrors时才通过Convert返回，返回ErrFallthrough时交由内置转换器处理，
+// 返回嵌套转换（Converter.Convert）得到的ConvertErrors时按原样合并，路径接在当前字段之后
+type CustomConverterBuilder interface {
+	Build(dstTyp, srcTyp reflect.Type, option *StructOption) func(dPtr, sPtr unsafe.Pointer) (bool, error)
+}
+
+// CustomConverterPriority 自定义转换器可选实现，多个转换器匹配同一类型对时优先级高的生效，未实现时为0
+type CustomConverterPriority interface {
+	Priority() int
+}
+
+// ErrFallthrough 自定义转换器（Build、ConverterE、Func、FuncInto）返回时放弃本次转换，交由内置转换器处理
+var ErrFallthrough = errors.New("[conv]fallthrough")
+
 type customConverter struct {
-	version int8
-	cvtOp   func(unsafe.Pointer, unsafe.Pointer)
-	cvtOpV2 func(unsafe.Pointer, unsafe.Pointer) bool
+	version  int8
+	cvtOp    func(unsafe.Pointer, unsafe.Pointer)
+	cvtOpV2  func(unsafe.Pointer, unsafe.Pointer) bool
+	cvtOpE   func(unsafe.Pointer, unsafe.Pointer) (bool, error)
+	dstTyp   reflect.Type
+	srcTyp   reflect.Type
+	key      string
+	priority int
+	fallback converter // 放行时使用的内置转换器，可能为nil
 }
 
 func Custom(cvtOp func(unsafe.Pointer, unsafe.Pointer)) converter {
@@ -23,10 +53,205 @@ func CustomV2(cvtOp func(unsafe.Pointer, unsafe.Pointer) bool) converter {
 	return &customConverter{version: 2, cvtOpV2: cvtOp}
 }
 
-func (c *customConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
-	if c.version == 1 {
+func (c *customConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	switch c.version {
+	case 1:
 		c.cvtOp(dPtr, sPtr)
 		return true
+	case 3, 4:
+		ok, err := c.cvtOpE(dPtr, sPtr)
+		if errors.Is(err, ErrFallthrough) {
+			return c.fallback != nil && c.fallback.convert(dPtr, sPtr, cs)
+		}
+		if nested, ok := err.(ConvertErrors); ok {
+			return cs.merge(nested)
+		}
+		if err != nil {
+			if c.version == 4 {
+				return cs.fail(c.dstTyp, c.srcTyp, err)
+			}
+			return cs.failHook(c.dstTyp, c.srcTyp, err)
+		}
+		return ok
 	}
 	return c.cvtOpV2(dPtr, sPtr)
 }
+
+// newCustomConverter 按优先级选择自定义转换器，同优先级时CustomConverterV2优先于CustomConverter，再按注册顺序
+func newCustomConverter(typ *convertType) converter {
+	if typ.option == nil {
+		return nil
+	}
+	v2, p2, ok2 := selectCustom(typ.option.CustomConvV2, typ.dstTyp, typ.srcTyp)
+	v1, p1, ok1 := selectCustom(typ.option.CustomConv, typ.dstTyp, typ.srcTyp)
+	if ok1 && (!ok2 || p1 > p2) {
+		return &customConverter{version: 1, cvtOp: v1.Converter(), key: v1.Key(), priority: p1}
+	}
+	if ok2 {
+		return customV2Of(v2, typ.option, typ.dstTyp, typ.srcTyp)
+	}
+	return nil
+}
+
+// selectCustom 选出匹配类型对且优先级最高的自定义转换器，同优先级取先注册的
+func selectCustom[C interface {
+	Is(dstTyp, srcTyp reflect.Type) bool
+}](customs []C, dstTyp, srcTyp reflect.Type) (selected C, priority int, ok bool) {
+	for _, v := range customs {
+		if p := priorityOf(v); (!ok || p > priority) && v.Is(dstTyp, srcTyp) {
+			selected, priority, ok = v, p, true
+		}
+	}
+	return
+}
+
+func priorityOf(custom any) int {
+	if p, ok := custom.(CustomConverterPriority); ok {
+		return p.Priority()
+	}
+	return 0
+}
+
+// customV2Of 按类型对构建时使用Build，需要返回错误时使用ConverterE，需要读取配置时使用ConverterWithOption
+func customV2Of(custom CustomConverterV2, option *StructOption, dstTyp, srcTyp reflect.Type) converter {
+	key, priority := custom.Key(), priorityOf(custom)
+	for {
+		pc, ok := custom.(*priorityConverter)
+		if !ok {
+			break
+		}
+		custom = pc.CustomConverterV2
+	}
+	c := &customConverter{version: 2, dstTyp: dstTyp, srcTyp: srcTyp, key: key, priority: priority}
+	if cb, ok := custom.(CustomConverterBuilder); ok {
+		c.version, c.cvtOpE = 4, cb.Build(dstTyp, srcTyp, option)
+	} else if ce, ok := custom.(CustomConverterE); ok {
+		c.version, c.cvtOpE = 3, ce.ConverterE()
+	} else if cv, ok := custom.(CustomConverterWithOption); ok {
+		c.cvtOpV2 = cv.ConverterWithOption(option)
+	} else {
+		c.cvtOpV2 = custom.Converter()
+	}
+	return c
+}
+
+// priorityConverter 指定了优先级的自定义转换器
+type priorityConverter struct {
+	CustomConverterV2
+	priority int
+}
+
+// WithPriority 为自定义转换器指定优先级，覆盖其自身实现的Priority
+func WithPriority(custom CustomConverterV2, priority int) CustomConverterV2 {
+	return &priorityConverter{CustomConverterV2: custom, priority: priority}
+}
+
+func (p *priorityConverter) Priority() int {
+	return p.priority
+}
+
+func (p *priorityConverter) Key() string {
+	return fmt.Sprintf("%s[priority:%d]", p.CustomConverterV2.Key(), p.priority)
+}
+
+// funcConverter 由类型化函数生成的自定义转换器，按去掉指针后的类型匹配
+type funcConverter struct {
+	dstTyp     reflect.Type
+	srcTyp     reflect.Type
+	dReferDeep int
+	sReferDeep int
+	cvtOp      func(dPtr, sPtr unsafe.Pointer) (bool, error)
+	key        string
+}
+
+func newFuncConverter[To, From any](kind string, fn unsafe.Pointer) *funcConverter {
+	c := &funcConverter{}
+	c.dstTyp, c.dReferDeep = referDeep(ReflectType[To]())
+	c.srcTyp, c.sReferDeep = referDeep(ReflectType[From]())
+	c.key = fmt.Sprintf("[%s:%v<-%v:%p]", kind, ReflectType[To](), ReflectType[From](), fn)
+	return c
+}
+
+// NewFuncConverter 由func(From) (To, error)生成自定义转换器，返回nil指针时不写入目标
+func NewFuncConverter[To, From any](fn func(From) (To, error)) CustomConverterV2 {
+	c := newFuncConverter[To, From]("func", funcIdentity(fn))
+	c.cvtOp = func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+		to, err := fn(*(*From)(referPtr(sPtr, c.sReferDeep)))
+		if err != nil {
+			return false, err
+		}
+		if c.dReferDeep == 0 {
+			*(*To)(dPtr) = to
+			return true, nil
+		}
+		return c.setDst(dPtr, unsafe.Pointer(&to)), nil
+	}
+	return c
+}
+
+// NewFuncIntoConverter 由func(*To, From) error生成自定义转换器，直接写入目标
+func NewFuncIntoConverter[To, From any](fn func(*To, From) error) CustomConverterV2 {
+	c := newFuncConverter[To, From]("into", funcIdentity(fn))
+	c.cvtOp = func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+		toPtr := referPtr(dPtr, c.dReferDeep)
+		if err := fn((*To)(toPtr), *(*From)(referPtr(sPtr, c.sReferDeep))); err != nil {
+			return false, err
+		}
+		if c.dReferDeep == 0 {
+			return true, nil
+		}
+		return c.setDst(dPtr, toPtr), nil
+	}
+	return c
+}
+
+// Is 类型一致，或实际类型是以To/From（非自定义类型）为底层类型的自定义类型
+func (c *funcConverter) Is(dstTyp, srcTyp reflect.Type) bool {
+	return sameLayout(dstTyp, c.dstTyp) && sameLayout(srcTyp, c.srcTyp)
+}
+
+func (c *funcConverter) Converter() func(dPtr, sPtr unsafe.Pointer) bool {
+	return func(dPtr, sPtr unsafe.Pointer) bool {
+		ok, _ := c.cvtOp(dPtr, sPtr)
+		return ok
+	}
+}
+
+func (c *funcConverter) ConverterE() func(dPtr, sPtr unsafe.Pointer) (bool, error) {
+	return c.cvtOp
+}
+
+func (c *funcConverter) Key() string {
+	return c.key
+}
+
+// setDst toPtr指向To类型的值，逐层解引用后复制到dPtr，中间为nil时不写入
+func (c *funcConverter) setDst(dPtr, toPtr unsafe.Pointer) bool {
+	for i := 0; i < c.dReferDeep; i++ {
+		if toPtr = *(*unsafe.Pointer)(toPtr); toPtr == nil {
+			return false
+		}
+	}
+	if toPtr != dPtr {
+		reflect.NewAt(c.dstTyp, dPtr).Elem().Set(reflect.NewAt(c.dstTyp, toPtr).Elem())
+	}
+	return true
+}
+
+// referPtr 返回指向deep级指针的指针，其最终指向p
+func referPtr(p unsafe.Pointer, deep int) unsafe.Pointer {
+	for i := 0; i < deep; i++ {
+		pp := new(unsafe.Pointer)
+		*pp = p
+		p = unsafe.Pointer(pp)
+	}
+	return p
+}
+
+// sameLayout 类型一致，或want是内置类型（无包路径）且actual是以其为底层类型的自定义类型
+func sameLayout(actual, want reflect.Type) bool {
+	if actual == want {
+		return true
+	}
+	return want.PkgPath() == "" && actual.Kind() == want.Kind() && actual.Kind() != reflect.Interface && actual.ConvertibleTo(want)
+}
@@ -26,23 +26,39 @@ type elemConverter struct {
 	converter           converter
 }
 
-func newElemConverter(dType, sType reflect.Type, option *StructOption) (*elemConverter, bool) {
+// newElemConverter custom为字段级自定义转换器，优先于option中按类型匹配的转换器
+func newElemConverter(dType, sType reflect.Type, option *StructOption, custom ...CustomConverterV2) (*elemConverter, bool) {
 	ec := &elemConverter{dType: dType, sType: sType}
 	ec.dDereferType, ec.dReferDeep = referDeep(dType)
 	ec.sDereferType, ec.sReferDeep = referDeep(sType)
-	if c := newConverter(ec.dDereferType, ec.sDereferType, option); c != nil {
-		if ac, ok := IsAnyConverter(c); ok {
-			ac.SetSrcReferDeep(ec.sReferDeep)
+	var cc *customConverter
+	if v, _, ok := selectCustom(custom, ec.dDereferType, ec.sDereferType); ok {
+		cc = customV2Of(v, option, ec.dDereferType, ec.sDereferType).(*customConverter)
+		ec.converter = cc
+	}
+	// 字段级自定义转换器放行时使用按类型匹配的转换器
+	if cc == nil || cc.version >= 3 {
+		c := newConverter(ec.dDereferType, ec.sDereferType, option)
+		if c == nil && cc == nil {
+			return nil, false
+		}
+		if c != nil {
+			if ac, ok := IsAnyConverter(c); ok {
+				ac.SetSrcReferDeep(ec.sReferDeep)
+			}
+			if cc != nil {
+				cc.fallback = c
+			} else {
+				ec.converter = c
+			}
 		}
-		ec.converter = c
-		ec.sEmptyDereferValPtr = newValuePtr(ec.sDereferType)
-		ec.nilValuePolicy = option.NilValuePolicy
-		return ec, true
 	}
-	return nil, false
+	ec.sEmptyDereferValPtr = newValuePtr(ec.sDereferType)
+	ec.nilValuePolicy = option.NilValuePolicy
+	return ec, true
 }
 
-func (e *elemConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (e *elemConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
 	for i := 0; i < e.sReferDeep; i++ {
 		sPtr = unsafe.Pointer(*((**int)(sPtr)))
 		if sPtr == nil {
@@ -57,6 +73,9 @@ func (e *elemConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
 			break
 		}
 	}
+	if e.sReferDeep > 0 && e.dReferDeep > 0 && cs.tracking() {
+		return e.convertVisited(dPtr, sPtr, cs)
+	}
 	var deep int
 	for ; deep < e.dReferDeep; deep++ {
 		oldPtr := dPtr
@@ -68,7 +87,7 @@ func (e *elemConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
 	}
 	if deep := e.dReferDeep - deep; deep > 0 {
 		v := newValuePtr(e.dDereferType)
-		if !e.converter.convert(v, sPtr) {
+		if !e.converter.convert(v, sPtr, cs) {
 			return false
 		}
 		for i := 0; i < deep; i++ {
@@ -76,7 +95,83 @@ func (e *elemConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
 		}
 		*(**int)(dPtr) = *(**int)(v)
 	} else {
-		return e.converter.convert(dPtr, sPtr)
+		return e.converter.convert(dPtr, sPtr, cs)
+	}
+	return true
+}
+
+// convertVisited 记录已转换的源指针，同一源指针只转换一次
+func (e *elemConverter) convertVisited(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	// 找到最后一级指针，中间层级为nil时新分配
+	for i := 1; i < e.dReferDeep; i++ {
+		next := *(*unsafe.Pointer)(dPtr)
+		if next == nil {
+			next = unsafe.Pointer(new(unsafe.Pointer))
+			*(*unsafe.Pointer)(dPtr) = next
+		}
+		dPtr = next
+	}
+	entry, found := cs.visit(sPtr, e.dDereferType)
+	if found {
+		if entry.converting && cs.cycle == CyclePolicyError {
+			*(*unsafe.Pointer)(dPtr) = nil
+			return cs.fail(e.dDereferType, e.sDereferType, ErrCycle)
+		}
+		*(*unsafe.Pointer)(dPtr) = entry.dPtr
+		return true
 	}
+	target := *(*unsafe.Pointer)(dPtr)
+	if target == nil {
+		target = newValuePtr(e.dDereferType)
+	}
+	entry.dPtr = target
+	if !e.converter.convert(target, sPtr, cs) {
+		cs.unvisit(sPtr, e.dDereferType)
+		return false
+	}
+	entry.converting = false
+	*(*unsafe.Pointer)(dPtr) = target
 	return true
 }
+
+// ElemConverter 按给定类型（可为多级指针）直接转换，转换时不做类型校验
+type ElemConverter struct {
+	*elemConverter
+	option    *StructOption
+	dElemSize uintptr
+	sElemSize uintptr
+	hooked    bool
+	dynamic   bool
+}
+
+func NewElemConverter(dType, sType reflect.Type, option *StructOption) *ElemConverter {
+	createdConvertersMu.Lock()
+	defer createdConvertersMu.Unlock()
+	if ec, ok := newElemConverter(dType, sType, option); ok {
+		c := &ElemConverter{elemConverter: ec, option: option, dElemSize: dType.Size(), sElemSize: sType.Size()}
+		c.hooked, c.dynamic = chainHooks(ec)
+		return c
+	}
+	return nil
+}
+
+// Convert dPtr和sPtr分别指向dType和sType类型的值
+func (e *ElemConverter) Convert(dPtr, sPtr unsafe.Pointer) error {
+	cs := newRootState(e.option, e.dType, e.hooked, e.dynamic, false)
+	cs.visitRoot(dPtr, sPtr, e.dType)
+	e.convert(dPtr, sPtr, cs)
+	return cs.release()
+}
+
+// ConvertSlice dPtr和sPtr分别指向长度为length的dType和sType类型数组的首个元素
+func (e *ElemConverter) ConvertSlice(dPtr, sPtr unsafe.Pointer, length int) error {
+	cs := newRootState(e.option, reflect.SliceOf(e.dType), e.hooked, e.dynamic, false)
+	for dOffset, sOffset, i := uintptr(0), uintptr(0), 0; i < length; i++ {
+		cs.pushIndex(i)
+		e.convert(unsafe.Pointer(uintptr(dPtr)+dOffset), unsafe.Pointer(uintptr(sPtr)+sOffset), cs)
+		cs.pop()
+		dOffset += e.dElemSize
+		sOffset += e.sElemSize
+	}
+	return cs.release()
+}
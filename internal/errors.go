@@ -0,0 +1,259 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/smgrushb/conv/internal/ptr"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+var (
+	// ErrUnsupportedType 运行时遇到无法转换的实际类型（如any中的值）
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrOutOfRange 严格模式下数值转换溢出或丢失精度
+	ErrOutOfRange = ptr.ErrOutOfRange
+	// ErrCycle CyclePolicyError下源数据中指针成环
+	ErrCycle = errors.New("reference cycle detected")
+	// ErrInvalidTag 字段tag中的unit或tz无法识别，该字段每次转换都返回此错误
+	ErrInvalidTag = errors.New("invalid tag")
+)
+
+// ConvertError 单个字段的转换错误
+type ConvertError struct {
+	Path   string       // 目标字段路径，如 Order.Items[3].Price
+	SrcTyp reflect.Type // 源类型
+	DstTyp reflect.Type // 目标类型
+	Err    error        // 原始错误
+}
+
+func (e *ConvertError) Error() string {
+	return fmt.Sprintf("[conv]%s: can't convert %v to %v: %v", e.Path, e.SrcTyp, e.DstTyp, e.Err)
+}
+
+func (e *ConvertError) Unwrap() error {
+	return e.Err
+}
+
+// ConvertErrors 一次转换中收集到的全部错误
+type ConvertErrors []*ConvertError
+
+func (e ConvertErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(len(e)))
+	sb.WriteString(" errors occurred:")
+	for _, v := range e {
+		sb.WriteString("\n\t")
+		sb.WriteString(v.Error())
+	}
+	return sb.String()
+}
+
+// Is 任一错误匹配target，Go 1.20之前errors.Is不支持Unwrap() []error
+func (e ConvertErrors) Is(target error) bool {
+	for _, v := range e {
+		if errors.Is(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 第一个能匹配target的错误，同Is
+func (e ConvertErrors) As(target any) bool {
+	for _, v := range e {
+		if errors.As(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e ConvertErrors) Unwrap() []error {
+	res := make([]error, len(e))
+	for i, v := range e {
+		res[i] = v
+	}
+	return res
+}
+
+// convState 单次转换的运行时状态，不收集错误且不检测成环时为nil
+type convState struct {
+	root    string
+	path    []string
+	errs    ConvertErrors
+	report  bool
+	cycle   CyclePolicy
+	visited map[visitKey]*visitEntry
+	// 记录写入的目标字段，见ConvertMask
+	maskPath   []string
+	masks      []string
+	maskSet    map[string]bool
+	maskHits   int
+	maskMuteAt int // 进入重复字段时的路径深度，其下不再记录
+	// 只收集经过any时实际类型的转换链路中钩子返回的错误，不记录路径，见newRootState
+	quiet bool
+}
+
+// quietStates 复用quiet状态，避免经过any的转换每次分配
+var quietStates = sync.Pool{New: func() any { return &convState{quiet: true} }}
+
+// visitKey 同一源指针转换到不同目标类型时分别记录
+type visitKey struct {
+	sPtr   unsafe.Pointer
+	dstTyp reflect.Type
+}
+
+type visitEntry struct {
+	dPtr       unsafe.Pointer
+	converting bool // 正在转换中，再次遇到说明成环
+}
+
+// newConvState hooked为true时转换链路中有钩子，需要传递钩子返回的错误，masking为true时记录写入的目标字段
+func newConvState(option *StructOption, dstTyp reflect.Type, hooked, masking bool) *convState {
+	if option == nil {
+		option = &StructOption{}
+	}
+	if !hooked && !masking && !option.ReportErrors && option.CyclePolicy == CyclePolicyNone {
+		return nil
+	}
+	cs := &convState{report: option.ReportErrors, cycle: option.CyclePolicy}
+	if masking {
+		cs.maskSet = make(map[string]bool)
+	}
+	if cs.root = dstTyp.Name(); len(cs.root) == 0 {
+		cs.root = dstTyp.String()
+	}
+	if cs.cycle != CyclePolicyNone {
+		cs.visited = make(map[visitKey]*visitEntry)
+	}
+	return cs
+}
+
+// newRootState 同newConvState，dynamic为true且不需要其他状态时返回quiet状态，
+// 用于接收运行时才构建的转换链路中钩子返回的错误，转换完成后调用release
+func newRootState(option *StructOption, dstTyp reflect.Type, hooked, dynamic, masking bool) *convState {
+	cs := newConvState(option, dstTyp, hooked, masking)
+	if cs == nil && dynamic {
+		cs = quietStates.Get().(*convState)
+	}
+	return cs
+}
+
+// release 返回收集的错误，quiet状态放回复用
+func (cs *convState) release() error {
+	err := cs.err()
+	if cs != nil && cs.quiet {
+		cs.errs = nil
+		quietStates.Put(cs)
+	}
+	return err
+}
+
+func (cs *convState) reporting() bool {
+	return cs != nil && cs.report
+}
+
+func (cs *convState) tracking() bool {
+	return cs != nil && cs.visited != nil
+}
+
+func (cs *convState) pushField(name string) {
+	if cs != nil && !cs.quiet {
+		cs.path = append(cs.path, "."+name)
+	}
+}
+
+func (cs *convState) pushIndex(i int) {
+	if cs != nil && !cs.quiet {
+		cs.path = append(cs.path, "["+strconv.Itoa(i)+"]")
+	}
+}
+
+func (cs *convState) pushKey(key string) {
+	if cs != nil && !cs.quiet {
+		cs.path = append(cs.path, "["+key+"]")
+	}
+}
+
+func (cs *convState) pop() {
+	if cs != nil && !cs.quiet {
+		cs.path = cs.path[:len(cs.path)-1]
+	}
+}
+
+// fail 记录转换错误，始终返回false
+func (cs *convState) fail(dstTyp, srcTyp reflect.Type, err error) bool {
+	if cs.reporting() {
+		cs.errs = append(cs.errs, &ConvertError{Path: cs.root + strings.Join(cs.path, ""), SrcTyp: srcTyp, DstTyp: dstTyp, Err: err})
+	}
+	return false
+}
+
+// failHook 记录钩子、自定义函数返回的错误或无效配置，不受ReportErrors控制，始终返回false
+func (cs *convState) failHook(dstTyp, srcTyp reflect.Type, err error) bool {
+	if cs != nil {
+		cs.errs = append(cs.errs, &ConvertError{Path: cs.root + strings.Join(cs.path, ""), SrcTyp: srcTyp, DstTyp: dstTyp, Err: err})
+	}
+	return false
+}
+
+// merge 合并嵌套转换返回的错误，已按嵌套转换的配置过滤，路径去掉嵌套的根类型后接在当前位置之后，始终返回false
+func (cs *convState) merge(errs ConvertErrors) bool {
+	if cs == nil {
+		return false
+	}
+	for _, e := range errs {
+		merged := *e
+		if !cs.quiet {
+			var sub string
+			if i := strings.IndexAny(e.Path, ".["); i >= 0 {
+				sub = e.Path[i:]
+			}
+			merged.Path = cs.root + strings.Join(cs.path, "") + sub
+		}
+		cs.errs = append(cs.errs, &merged)
+	}
+	return false
+}
+
+// visitRoot 记录根对象，成环指回根对象时复用目标
+func (cs *convState) visitRoot(dPtr, sPtr unsafe.Pointer, dstTyp reflect.Type) {
+	if cs.tracking() {
+		cs.visited[visitKey{sPtr: sPtr, dstTyp: dstTyp}] = &visitEntry{dPtr: dPtr, converting: true}
+	}
+}
+
+// visit 查找源指针已转换的目标，found为false时由调用方转换并通过返回的entry登记
+func (cs *convState) visit(sPtr unsafe.Pointer, dstTyp reflect.Type) (entry *visitEntry, found bool) {
+	key := visitKey{sPtr: sPtr, dstTyp: dstTyp}
+	if entry, found = cs.visited[key]; found {
+		return
+	}
+	entry = &visitEntry{converting: true}
+	cs.visited[key] = entry
+	return
+}
+
+// unvisit 转换失败时删除登记
+func (cs *convState) unvisit(sPtr unsafe.Pointer, dstTyp reflect.Type) {
+	delete(cs.visited, visitKey{sPtr: sPtr, dstTyp: dstTyp})
+}
+
+func (cs *convState) err() error {
+	if cs == nil || len(cs.errs) == 0 {
+		return nil
+	}
+	return cs.errs
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// Plan 类型对的转换计划
+type Plan struct {
+	DstTyp    reflect.Type
+	SrcTyp    reflect.Type
+	Converter ConverterInfo // 整体选中的转换器，无法转换时Kind为none
+	Fields    []FieldPlan   // 目标结构体的字段，只有按字段转换的结构体之间才有
+	UnusedSrc []string      // 没有对应目标字段的源字段
+}
+
+// FieldPlan 目标字段的转换计划
+type FieldPlan struct {
+	Dst       string
+	Name      string // 匹配源字段用的名字（tag或别名）
+	DstType   reflect.Type
+	Src       string // 源字段名，方法为Name()，没有对应的源字段时为空
+	SrcType   reflect.Type
+	Converter ConverterInfo // 只有Status为ok时才有
+	Status    FieldStatus
+}
+
+// Explain 构建转换器并返回转换计划，结构体之间无法转换时也会列出每个字段的原因
+func Explain(dstTyp, srcTyp reflect.Type, option *StructOption) *Plan {
+	dTyp, _ := dereferencedType(dstTyp)
+	sTyp, _ := dereferencedType(srcTyp)
+	p := &Plan{DstTyp: dTyp, SrcTyp: sTyp, Converter: ConverterInfo{Kind: "none"}}
+	if c := NewConverter(dstTyp, srcTyp, option); c != nil {
+		p.Converter = inspect(c.converter)
+	}
+	if dTyp.Kind() != reflect.Struct || sTyp.Kind() != reflect.Struct || (p.Converter.Kind != "struct" && p.Converter.Kind != "proto" && p.Converter.Kind != "none") {
+		return p
+	}
+	same := dTyp == sTyp && option.wholeStruct()
+	if (isProtoStruct(dTyp) || isProtoStruct(sTyp)) && !same {
+		createdConvertersMu.Lock()
+		defer createdConvertersMu.Unlock()
+		p.explainProto(option)
+		return p
+	}
+	// 同类型直接按位复制
+	if same {
+		for i := 0; i < dTyp.NumField(); i++ {
+			f := dTyp.Field(i)
+			p.Fields = append(p.Fields, FieldPlan{Dst: f.Name, DstType: f.Type, Src: f.Name, SrcType: f.Type, Name: f.Name, Converter: ConverterInfo{Kind: "copy"}, Status: FieldStatusOK})
+		}
+		return p
+	}
+	createdConvertersMu.Lock()
+	defer createdConvertersMu.Unlock()
+	p.explainFields(option)
+	return p
+}
+
+// explainFields 与newStructConverter的字段匹配规则一致
+func (p *Plan) explainFields(option *StructOption) {
+	sFields, sFieldIndex, dFieldIndex := extractStructFields(&convertType{dstTyp: p.DstTyp, srcTyp: p.SrcTyp, option: option})
+	used := make(map[*structItem]bool)
+	for _, df := range dFieldIndex {
+		fp := FieldPlan{Dst: df.filedName, DstType: df.typ, Status: FieldStatusOK}
+		banned := option != nil && option.BannedFields != nil && option.BannedFields.Contains(df.name)
+		if alias, ok := option.aliasField(df.name); ok && !banned {
+			df.name = alias
+		}
+		fp.Name = df.name
+		sf, matched := sFields[df.name]
+		if matched {
+			fp.Src, fp.SrcType = sf.displayName(), sf.typ
+			used[sf] = true
+		}
+		switch {
+		case banned:
+			fp.Status = FieldStatusBanned
+		case !matched:
+			fp.Status = FieldStatusUnmatched
+		case option != nil && option.WhiteListFields != nil && !option.WhiteListFields.Empty() && !option.WhiteListFields.Contains(sf.name), option.maskExcluded(sf.name):
+			fp.Status = FieldStatusFiltered
+		default:
+			var nestOption *StructOption
+			if option != nil && option.NestedOption != nil {
+				nestOption = option.NestedOption[df.name]
+			}
+			if nestOption == nil {
+				nestOption = option
+			}
+			nestOption = option.maskNested(nestOption, sf.name)
+			if fc := newFieldConverter(*df, *sf, nestOption, option.fieldConv(df.name)); fc != nil {
+				fp.Converter = inspect(fc.converter)
+			} else {
+				fp.Status = FieldStatusUnconvertible
+			}
+		}
+		p.Fields = append(p.Fields, fp)
+	}
+	for _, sf := range sFieldIndex {
+		if !used[sf] {
+			p.UnusedSrc = append(p.UnusedSrc, sf.displayName())
+		}
+	}
+}
+
+// explainProto 与newProtoConverter的字段匹配规则一致，消息一侧为proto字段名
+func (p *Plan) explainProto(option *StructOption) {
+	matches, unused := matchProtoFields(&convertType{dstTyp: p.DstTyp, srcTyp: p.SrcTyp, option: option})
+	for _, m := range matches {
+		fp := FieldPlan{Dst: m.dName, Name: m.name, Status: m.status}
+		if m.dField != nil {
+			fp.DstType = m.dField.goTyp
+		} else {
+			fp.DstType = m.dItem.typ
+		}
+		if m.sField != nil {
+			fp.Src, fp.SrcType = string(m.sField.fd.Name()), m.sField.goTyp
+		} else if m.sItem != nil {
+			fp.Src, fp.SrcType = m.sItem.displayName(), m.sItem.typ
+		}
+		if fp.Status == FieldStatusOK {
+			if fc := m.newConverter(option); fc != nil {
+				fp.Converter = inspect(fc.converter.converter)
+			} else {
+				fp.Status = FieldStatusUnconvertible
+			}
+		}
+		p.Fields = append(p.Fields, fp)
+	}
+	p.UnusedSrc = unused
+}
+
+// String 以表格形式输出
+func (p *Plan) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v <- %v: %v\n", p.DstTyp, p.SrcTyp, p.Converter)
+	if len(p.Fields) > 0 {
+		w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "FIELD\tTYPE\tSOURCE\tSOURCE TYPE\tCONVERTER\tSTATUS")
+		for _, f := range p.Fields {
+			conv := "-"
+			if f.Status == FieldStatusOK {
+				conv = f.Converter.String()
+			}
+			dst := f.Dst
+			if f.Name != "" && f.Name != f.Dst {
+				dst += " [" + f.Name + "]"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", dst, typeName(f.DstType), valid(f.Src), typeName(f.SrcType), conv, f.Status)
+		}
+		_ = w.Flush()
+	}
+	if len(p.UnusedSrc) > 0 {
+		fmt.Fprintf(&sb, "unused source fields: %s\n", strings.Join(p.UnusedSrc, ", "))
+	}
+	return sb.String()
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "-"
+	}
+	return t.String()
+}
+
+func valid(s string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+	return s
+}
+
+func (s *structItem) displayName() string {
+	switch s.itemType {
+	case typeFieldMethod:
+		return s.filedName + "()"
+	case typeMethod:
+		return s.name + "()"
+	}
+	return s.filedName
+}
+
+func (o *StructOption) aliasField(name string) (string, bool) {
+	if o == nil {
+		return "", false
+	}
+	alias, ok := o.AliasFields[name]
+	return alias, ok
+}
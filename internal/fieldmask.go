@@ -0,0 +1,132 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"github.com/smgrushb/conv/internal/generics/collection/set"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// FieldMaskName FieldMask路径归一化，忽略大小写和下划线，display_name、displayName、DisplayName视为同一字段
+func FieldMaskName(path string) string {
+	return strings.ToLower(strings.ReplaceAll(path, "_", ""))
+}
+
+// maskExcluded 指定了FieldMask且字段不在其中
+func (o *StructOption) maskExcluded(name string) bool {
+	if o == nil || o.FieldMaskFields.Empty() {
+		return false
+	}
+	name = FieldMaskName(name)
+	prefix := name + "."
+	excluded := true
+	o.FieldMaskFields.ForEach(func(path string) {
+		excluded = excluded && path != name && !strings.HasPrefix(path, prefix)
+	})
+	return excluded
+}
+
+// maskNested 指定了FieldMask时字段使用的配置，FieldMask为该字段的下级路径，没有下级路径时整体转换
+func (o *StructOption) maskNested(nest *StructOption, name string) *StructOption {
+	if o == nil || o.FieldMaskFields.Empty() {
+		return nest
+	}
+	name = FieldMaskName(name)
+	prefix := name + "."
+	var whole bool
+	var paths []string
+	o.FieldMaskFields.ForEach(func(path string) {
+		if path == name {
+			whole = true
+		} else if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path[len(prefix):])
+		}
+	})
+	res := nest.Clone()
+	res.FieldMaskFields = set.New[string]()
+	if !whole {
+		res.FieldMaskFields.AddN(paths...)
+	}
+	return res
+}
+
+// enterMask 进入目标字段，返回进入前记录的次数，未开启记录或处于重复字段之下时返回-1，
+// 进入切片、数组、map字段后其下的字段不再记录，与fieldmaskpb一致
+func (cs *convState) enterMask(name string, repeated bool) int {
+	if cs == nil || cs.maskSet == nil || cs.maskMuteAt > 0 {
+		return -1
+	}
+	cs.maskPath = append(cs.maskPath, name)
+	if repeated {
+		cs.maskMuteAt = len(cs.maskPath)
+	}
+	return cs.maskHits
+}
+
+// leaveMask 目标字段有值且其下没有记录到子字段时记录该字段的路径，hits为enterMask的返回值
+func (cs *convState) leaveMask(hits int, populated bool) {
+	if hits < 0 {
+		return
+	}
+	if cs.maskMuteAt == len(cs.maskPath) {
+		cs.maskMuteAt = 0
+	}
+	if populated && cs.maskHits == hits {
+		if path := strings.Join(cs.maskPath, "."); !cs.maskSet[path] {
+			cs.maskSet[path] = true
+			cs.masks = append(cs.masks, path)
+		}
+		cs.maskHits++
+	}
+	cs.maskPath = cs.maskPath[:len(cs.maskPath)-1]
+}
+
+// fieldMask 记录的路径，去掉已被上级路径覆盖的（如根对象为切片时部分元素的字段整体写入）
+func (cs *convState) fieldMask() []string {
+	var res []string
+	for _, path := range cs.masks {
+		covered := false
+		for i := strings.IndexByte(path, '.'); i > 0 && !covered; i = nextDot(path, i) {
+			covered = cs.maskSet[path[:i]]
+		}
+		if !covered {
+			res = append(res, path)
+		}
+	}
+	return res
+}
+
+func nextDot(s string, i int) int {
+	if j := strings.IndexByte(s[i+1:], '.'); j >= 0 {
+		return i + 1 + j
+	}
+	return -1
+}
+
+// isRepeatedType 切片、数组、map，FieldMask的路径到此为止
+func isRepeatedType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// populatedAt 目标字段是否有值，经过的嵌入指针为nil时视为没有值
+func populatedAt(p unsafe.Pointer, anonymousPtr []bool, offset []uintptr, typ reflect.Type) bool {
+	p = unsafe.Pointer(uintptr(p) + offset[0])
+	for i, isPtr := range anonymousPtr {
+		if isPtr {
+			if p = *(*unsafe.Pointer)(p); p == nil {
+				return false
+			}
+		}
+		p = unsafe.Pointer(uintptr(p) + offset[i+1])
+	}
+	return !reflect.NewAt(typ, p).Elem().IsZero()
+}
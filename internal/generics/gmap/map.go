@@ -10,6 +10,15 @@ func Contains[K comparable, V any](m map[K]V, k K) bool {
 	return ok
 }
 
+// Keys 返回map的所有key，顺序不固定
+func Keys[K comparable, V any](m map[K]V) []K {
+	res := make([]K, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
+
 // MapValues 将指定map内的value进行一次映射
 func MapValues[K comparable, V1, V2 any](m map[K]V1, f func(V1) V2) map[K]V2 {
 	if m == nil {
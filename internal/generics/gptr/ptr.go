@@ -15,3 +15,12 @@ func Zero[T any]() *T {
 // 如果已定义变量，调用方法时会进行一次值拷贝，返回地址为拷贝后的数据的地址。
 // 请根据实际需求判断是否符合预期，如不符合预期，请使用取址符 &
 func Of[T any](v T) *T { return &v }
+
+// Indirect 解引用，nil返回零值
+func Indirect[T any](p *T) T {
+	if p == nil {
+		var t T
+		return t
+	}
+	return *p
+}
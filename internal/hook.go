@@ -0,0 +1,222 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// BeforeConverter 目标结构体（指针接收者）实现，字段转换前调用，src为指向源值的指针
+type BeforeConverter interface {
+	BeforeConv(src any) error
+}
+
+// AfterConverter 目标结构体（指针接收者）实现，字段转换完成后调用，src为指向源值的指针
+type AfterConverter interface {
+	AfterConv(src any) error
+}
+
+// BeforeConverterTo 源结构体（指针接收者）实现，字段转换前调用，dst为指向目标值的指针
+type BeforeConverterTo interface {
+	BeforeConvTo(dst any) error
+}
+
+// AfterConverterTo 源结构体（指针接收者）实现，字段转换完成后调用，dst为指向目标值的指针
+type AfterConverterTo interface {
+	AfterConvTo(dst any) error
+}
+
+var (
+	beforeConverterType   = ReflectType[BeforeConverter]()
+	afterConverterType    = ReflectType[AfterConverter]()
+	beforeConverterToType = ReflectType[BeforeConverterTo]()
+	afterConverterToType  = ReflectType[AfterConverterTo]()
+)
+
+type hookFunc = func(dPtr, sPtr unsafe.Pointer) error
+
+// Hook 按类型对注册的结构体转换钩子
+type Hook struct {
+	DstTyp reflect.Type
+	SrcTyp reflect.Type
+	Before hookFunc
+	After  hookFunc
+	key    string
+}
+
+// NewHook 钩子按函数实例区分，应复用同一个函数，避免每次转换都新建闭包导致转换器重复构建
+func NewHook[To, From any](before, after func(dst *To, src *From) error) Hook {
+	h := Hook{DstTyp: ReflectType[To](), SrcTyp: ReflectType[From]()}
+	if before != nil {
+		h.Before = func(dPtr, sPtr unsafe.Pointer) error {
+			return before((*To)(dPtr), (*From)(sPtr))
+		}
+	}
+	if after != nil {
+		h.After = func(dPtr, sPtr unsafe.Pointer) error {
+			return after((*To)(dPtr), (*From)(sPtr))
+		}
+	}
+	h.key = fmt.Sprintf("[hook:%v<-%v:%p:%p]", h.DstTyp, h.SrcTyp, funcIdentity(before), funcIdentity(after))
+	return h
+}
+
+func (h Hook) Key() string {
+	return h.key
+}
+
+// funcIdentity 函数值指向的闭包对象，同一函数字面量创建的不同闭包也能区分
+func funcIdentity[F any](fn F) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&fn))
+}
+
+// structHooks 结构体转换前后调用的钩子，没有钩子时为nil
+type structHooks struct {
+	before    []hookFunc
+	after     []hookFunc
+	dstBefore bool
+	dstAfter  bool
+	srcBefore bool
+	srcAfter  bool
+}
+
+func newStructHooks(typ *convertType) *structHooks {
+	dPtrTyp, sPtrTyp := reflect.PointerTo(typ.dstTyp), reflect.PointerTo(typ.srcTyp)
+	h := &structHooks{
+		dstBefore: dPtrTyp.Implements(beforeConverterType),
+		dstAfter:  dPtrTyp.Implements(afterConverterType),
+		srcBefore: sPtrTyp.Implements(beforeConverterToType),
+		srcAfter:  sPtrTyp.Implements(afterConverterToType),
+	}
+	if typ.option != nil {
+		for _, v := range typ.option.Hooks {
+			if v.DstTyp != typ.dstTyp || v.SrcTyp != typ.srcTyp {
+				continue
+			}
+			if v.Before != nil {
+				h.before = append(h.before, v.Before)
+			}
+			if v.After != nil {
+				h.after = append(h.after, v.After)
+			}
+		}
+	}
+	if len(h.before) == 0 && len(h.after) == 0 && !h.dstBefore && !h.dstAfter && !h.srcBefore && !h.srcAfter {
+		return nil
+	}
+	return h
+}
+
+// runBefore 依次调用配置的钩子、源类型实现的BeforeConvTo、目标类型实现的BeforeConv，出错时中止
+func (h *structHooks) runBefore(typ *convertType, dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	for _, fn := range h.before {
+		if err := fn(dPtr, sPtr); err != nil {
+			return cs.failHook(typ.dstTyp, typ.srcTyp, err)
+		}
+	}
+	if h.srcBefore {
+		if err := reflect.NewAt(typ.srcTyp, sPtr).Interface().(BeforeConverterTo).BeforeConvTo(reflect.NewAt(typ.dstTyp, dPtr).Interface()); err != nil {
+			return cs.failHook(typ.dstTyp, typ.srcTyp, err)
+		}
+	}
+	if h.dstBefore {
+		if err := reflect.NewAt(typ.dstTyp, dPtr).Interface().(BeforeConverter).BeforeConv(reflect.NewAt(typ.srcTyp, sPtr).Interface()); err != nil {
+			return cs.failHook(typ.dstTyp, typ.srcTyp, err)
+		}
+	}
+	return true
+}
+
+// runAfter 依次调用目标类型实现的AfterConv、源类型实现的AfterConvTo、配置的钩子，出错时中止
+func (h *structHooks) runAfter(typ *convertType, dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	if h.dstAfter {
+		if err := reflect.NewAt(typ.dstTyp, dPtr).Interface().(AfterConverter).AfterConv(reflect.NewAt(typ.srcTyp, sPtr).Interface()); err != nil {
+			return cs.failHook(typ.dstTyp, typ.srcTyp, err)
+		}
+	}
+	if h.srcAfter {
+		if err := reflect.NewAt(typ.srcTyp, sPtr).Interface().(AfterConverterTo).AfterConvTo(reflect.NewAt(typ.dstTyp, dPtr).Interface()); err != nil {
+			return cs.failHook(typ.dstTyp, typ.srcTyp, err)
+		}
+	}
+	for _, fn := range h.after {
+		if err := fn(dPtr, sPtr); err != nil {
+			return cs.failHook(typ.dstTyp, typ.srcTyp, err)
+		}
+	}
+	return true
+}
+
+// chainWalker 构建完成后遍历转换链路，判断转换时是否需要状态
+type chainWalker struct {
+	visited map[*Converter]bool
+	hooked  bool // 有钩子、返回错误的自定义转换器或无效的tag
+	dynamic bool // 有any转非any或Build构建的自定义转换器，实际的转换链路运行时才构建
+}
+
+// chainHooks 返回转换链路中是否有钩子、是否有运行时才构建的部分
+func chainHooks(c converter) (hooked, dynamic bool) {
+	w := &chainWalker{visited: make(map[*Converter]bool)}
+	w.walk(c)
+	return w.hooked, w.dynamic
+}
+
+func (w *chainWalker) walk(c converter) {
+	if w.hooked && w.dynamic {
+		return
+	}
+	switch cc := c.(type) {
+	case *Converter:
+		if !w.visited[cc] {
+			w.visited[cc] = true
+			w.walk(cc.converter)
+		}
+	case *elemConverter:
+		if cc != nil {
+			w.walk(cc.converter)
+		}
+	case *oneofConverter:
+		w.walk(cc.converter)
+	case *customConverter:
+		w.hooked = w.hooked || cc.version == 3
+		// Build构建的转换函数可能在内部转换嵌套的值，需要接收其中钩子返回的错误
+		w.dynamic = w.dynamic || cc.version == 4
+		if cc.fallback != nil {
+			w.walk(cc.fallback)
+		}
+	case *fromAnyConverter:
+		w.dynamic = true
+	case *invalidConverter:
+		w.hooked = true
+	case *structConverter:
+		w.hooked = w.hooked || cc.hooks != nil
+		for _, f := range cc.fieldConverters {
+			w.walk(f)
+		}
+	case *fieldConverter:
+		w.walk(cc.converter)
+	case *fieldMapConverter:
+		w.walk(cc.converter)
+	case *mapFieldConverter:
+		w.walk(cc.converter)
+	case *protoConverter:
+		w.hooked = w.hooked || cc.hooks != nil
+		for _, f := range cc.fields {
+			if f.converter != nil {
+				w.walk(f.converter)
+			}
+		}
+	case *sliceConverter:
+		w.walk(cc.elemConverter)
+	case *arrayConverter:
+		w.walk(cc.elemConverter)
+	case *mapConverter:
+		w.walk(cc.keyConverter)
+		w.walk(cc.valConverter)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+type chainPlain struct {
+	A int
+}
+
+type chainHooked struct {
+	A int
+}
+
+func (c *chainHooked) AfterConv(any) error {
+	return nil
+}
+
+type chainWrap struct {
+	P chainPlain
+}
+
+type chainA struct {
+	B *chainB
+	H chainHooked
+}
+
+type chainB struct {
+	A *chainA
+}
+
+type chainASrc struct {
+	B *chainBSrc
+	H chainPlain
+}
+
+type chainBSrc struct {
+	A *chainASrc
+}
+
+// 钩子和any转非any由构建结果得到，循环引用时先构建的一侧的钩子也要传递给另一侧
+func TestChainHooks(t *testing.T) {
+	mapTyp := ReflectType[map[string]any]()
+	cases := []struct {
+		name            string
+		dstTyp, srcTyp  reflect.Type
+		hooked, dynamic bool
+	}{
+		{"plain", ReflectType[chainPlain](), ReflectType[chainHooked](), false, false},
+		{"hooked", ReflectType[chainHooked](), ReflectType[chainPlain](), true, false},
+		{"fromAny", ReflectType[chainWrap](), mapTyp, false, true},
+		{"cycle", ReflectType[chainA](), ReflectType[chainASrc](), true, false},
+		{"cycleInner", ReflectType[chainB](), ReflectType[chainBSrc](), true, false},
+	}
+	for _, c := range cases {
+		cv := NewConverter(c.dstTyp, c.srcTyp, newOption())
+		if cv == nil {
+			t.Fatalf("%s: no converter", c.name)
+		}
+		if cv.hooked != c.hooked || cv.dynamic != c.dynamic {
+			t.Fatalf("%s: want hooked=%v dynamic=%v, got %v %v", c.name, c.hooked, c.dynamic, cv.hooked, cv.dynamic)
+		}
+	}
+}
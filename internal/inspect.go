@@ -0,0 +1,105 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConverterInfo 类型对选中的转换器
+type ConverterInfo struct {
+	Kind     string // 转换器类别，如basic、time、struct、custom
+	Custom   string // 自定义转换器的Key
+	Priority int    // 自定义转换器的优先级
+	Fallback string // 自定义转换器放行时使用的转换器类别，不支持放行时为空
+}
+
+func (i ConverterInfo) String() string {
+	if i.Kind != "custom" {
+		return i.Kind
+	}
+	s := fmt.Sprintf("custom%s", i.Custom)
+	if i.Priority != 0 {
+		s += fmt.Sprintf(" priority:%d", i.Priority)
+	}
+	if i.Fallback != "" {
+		s += " fallback:" + i.Fallback
+	}
+	return s
+}
+
+// Inspect 构建转换器并返回选中的转换器，无法转换时返回false
+func Inspect(dstTyp, srcTyp reflect.Type, option *StructOption) (ConverterInfo, bool) {
+	c := NewConverter(dstTyp, srcTyp, option)
+	if c == nil {
+		return ConverterInfo{}, false
+	}
+	return inspect(c.converter), true
+}
+
+func inspect(c converter) ConverterInfo {
+	info := ConverterInfo{Kind: converterKind(c)}
+	if cc, ok := unwrapConverter(c).(*customConverter); ok {
+		info.Custom, info.Priority = cc.key, cc.priority
+		if cc.fallback != nil {
+			info.Fallback = converterKind(cc.fallback)
+		}
+	}
+	return info
+}
+
+func unwrapConverter(c converter) converter {
+	for {
+		switch cc := c.(type) {
+		case *Converter:
+			c = cc.converter
+		case *elemConverter:
+			c = cc.converter
+		case *oneofConverter:
+			c = cc.converter
+		default:
+			return c
+		}
+	}
+}
+
+// converterKind 转换器类别
+func converterKind(c converter) string {
+	switch unwrapConverter(c).(type) {
+	case *customConverter:
+		return "custom"
+	case *deepCopyConverter:
+		return "deepCopy"
+	case *stringsConverter:
+		return "strings"
+	case *marshalJsonConverter:
+		return "marshal"
+	case *serializeConverter:
+		return "serialize"
+	case *basicConverter:
+		return "basic"
+	case *timeConverter:
+		return "time"
+	case *anyConverter:
+		return "any"
+	case *fromAnyConverter:
+		return "fromAny"
+	case *protoConverter:
+		return "proto"
+	case *structConverter:
+		return "struct"
+	case *sliceConverter:
+		return "slice"
+	case *arrayConverter, *byteArrayConverter:
+		return "array"
+	case *mapConverter:
+		return "map"
+	case *invalidConverter:
+		return "invalid"
+	}
+	return "unknown"
+}
@@ -9,6 +9,7 @@
 package internal
 
 import (
+	"fmt"
 	"reflect"
 	"unsafe"
 )
@@ -19,6 +20,7 @@ type mapConverter struct {
 	dValType     reflect.Type
 	keyConverter *elemConverter
 	valConverter *elemConverter
+	mergeMode    MergeMode
 	enable       bool // 兜底
 }
 
@@ -29,6 +31,7 @@ func newMapConverter(typ *convertType) converter {
 		convertType: typ,
 		dKeyType:    dKeyTyp,
 		dValType:    dValTyp,
+		mergeMode:   typ.option.MergeMode,
 	}
 	key := typ.key()
 	// 先预注册进去，不然循环依赖下会循环解析
@@ -51,7 +54,7 @@ func newMapConverter(typ *convertType) converter {
 	return c
 }
 
-func (m *mapConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (m *mapConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
 	if !m.enable {
 		return false
 	}
@@ -65,10 +68,23 @@ func (m *mapConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
 		val := sv.MapIndex(sKey)
 		sValPtr := PtrOfAny(val)
 		sKeyPtr := PtrOfAny(sKey)
+		if skipMerge(m.mergeMode, m.valConverter.sType, sValPtr) {
+			continue
+		}
 		dKey := reflect.New(m.dKeyType).Elem()
 		dVal := reflect.New(m.dValType).Elem()
-		m.keyConverter.convert(unsafe.Pointer(dKey.UnsafeAddr()), sKeyPtr)
-		m.valConverter.convert(unsafe.Pointer(dVal.UnsafeAddr()), sValPtr)
+		if cs != nil {
+			cs.pushKey(fmt.Sprint(sKey.Interface()))
+		}
+		m.keyConverter.convert(unsafe.Pointer(dKey.UnsafeAddr()), sKeyPtr, cs)
+		// 合并模式下在目标已有的值上合并
+		if m.mergeMode != MergeModeOverwrite {
+			if old := dv.MapIndex(dKey); old.IsValid() {
+				dVal.Set(old)
+			}
+		}
+		m.valConverter.convert(unsafe.Pointer(dVal.UnsafeAddr()), sValPtr, cs)
+		cs.pop()
 		dv.SetMapIndex(dKey, dVal)
 	}
 	return true
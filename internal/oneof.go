@@ -0,0 +1,139 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"github.com/smgrushb/conv/internal/generics/gslice"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// oneofCases 把proto消息的oneof接口字段展开为各个分支，分支名与普通字段一致（IgnoreTag时为Go字段名，否则为proto字段名）
+func oneofCases(t reflect.Type, f reflect.StructField, opt *StructOption, anonymousPtr []bool, offset []uintptr) []*structItem {
+	od := reflect.New(t).Interface().(proto.Message).ProtoReflect().Descriptor().Oneofs().ByName(protoreflect.Name(f.Tag.Get("protobuf_oneof")))
+	if od == nil {
+		return nil
+	}
+	items := make([]*structItem, 0, od.Fields().Len())
+	for i := 0; i < od.Fields().Len(); i++ {
+		fd := od.Fields().Get(i)
+		// 设置该分支后从接口字段上取出包装类型，如*Msg_Card
+		mv := reflect.New(t)
+		m := mv.Interface().(proto.Message).ProtoReflect()
+		m.Set(fd, m.NewField(fd))
+		wrapperTyp := mv.Elem().FieldByIndex(f.Index).Elem().Type()
+		caseField := wrapperTyp.Elem().Field(0)
+		name := string(fd.Name())
+		if opt.IgnoreTag {
+			name = caseField.Name
+		}
+		items = append(items, &structItem{
+			itemType:     typeOneof,
+			name:         name,
+			filedName:    f.Name + "." + caseField.Name,
+			typ:          caseField.Type,
+			structType:   t,
+			anonymousPtr: anonymousPtr,
+			offset:       offset,
+			oneof:        string(od.Name()),
+			oneofIface:   f.Type,
+			oneofWrapper: wrapperTyp,
+		})
+	}
+	return items
+}
+
+// oneofNames oneof分支所属的oneof名，包括proto名和Go字段名
+func oneofNames(items []*structItem) map[string]bool {
+	var names map[string]bool
+	for _, v := range items {
+		if v.itemType == typeOneof {
+			if names == nil {
+				names = make(map[string]bool)
+			}
+			goName, _, _ := strings.Cut(v.filedName, ".")
+			names[v.oneof], names[goName] = true, true
+		}
+	}
+	return names
+}
+
+// expandOneofUnion 对侧有同名oneof时，把结构体字段视为联合体，像匿名字段一样展开，其字段按名字匹配oneof的分支
+func expandOneofUnion(items []*structItem, oneofs map[string]bool, opt *StructOption, fieldMap map[string]*structItem) []*structItem {
+	if len(oneofs) == 0 {
+		return items
+	}
+	if fieldMap == nil {
+		fieldMap = make(map[string]*structItem, len(items))
+		for _, v := range items {
+			fieldMap[v.name] = v
+		}
+	}
+	res := make([]*structItem, 0, len(items))
+	for _, v := range items {
+		if v.itemType != typeField || !oneofs[v.name] {
+			res = append(res, v)
+			continue
+		}
+		unionTyp, isPtr := dereferencedTypeDeep(v.typ)
+		if unionTyp.Kind() != reflect.Struct || isProtoMessage(reflect.PointerTo(unionTyp)) {
+			res = append(res, v)
+			continue
+		}
+		res = append(res, extractFields(unionTyp, opt, fieldMap, append(gslice.Clone(v.anonymousPtr), isPtr), v.offset...)...)
+	}
+	return res
+}
+
+// oneofConverter 把源值转换为oneof的一个分支，写入oneof接口字段
+type oneofConverter struct {
+	converter  *elemConverter
+	ifaceTyp   reflect.Type
+	wrapperTyp reflect.Type
+	skipZero   bool // 源不是oneof分支时零值视为未设置
+}
+
+func (o *oneofConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	if o.skipZero && reflect.NewAt(o.converter.sType, sPtr).Elem().IsZero() {
+		return false
+	}
+	w := reflect.New(o.wrapperTyp.Elem())
+	if !o.converter.convert(w.UnsafePointer(), sPtr, cs) {
+		return false
+	}
+	reflect.NewAt(o.ifaceTyp, dPtr).Elem().Set(w)
+	return true
+}
+
+// newOneofElemConverter 目标是oneof分支时，先转换为分支字段再包装写入接口字段
+func newOneofElemConverter(df, sf structItem, option *StructOption, custom []CustomConverterV2) (*elemConverter, bool) {
+	ec, ok := newElemConverter(df.typ, sf.typ, option, custom...)
+	if !ok {
+		return nil, false
+	}
+	return &elemConverter{
+		dType:               df.oneofIface,
+		sType:               sf.typ,
+		dDereferType:        df.oneofIface,
+		sDereferType:        sf.typ,
+		sEmptyDereferValPtr: newValuePtr(sf.typ),
+		nilValuePolicy:      option.NilValuePolicy,
+		converter:           &oneofConverter{converter: ec, ifaceTyp: df.oneofIface, wrapperTyp: df.oneofWrapper, skipZero: sf.itemType != typeOneof},
+	}, true
+}
+
+// oneofValue 源oneof接口字段当前是该分支时返回分支值的指针，否则返回nil
+func oneofValue(ifaceTyp, wrapperTyp reflect.Type, sPtr unsafe.Pointer) unsafe.Pointer {
+	v := reflect.NewAt(ifaceTyp, sPtr).Elem()
+	if v.IsNil() || v.Elem().Type() != wrapperTyp {
+		return nil
+	}
+	// 包装类型只有一个字段
+	return v.Elem().UnsafePointer()
+}
@@ -10,13 +10,17 @@ package internal
 
 import (
 	"fmt"
-	"github.com/bytedance/sonic/encoder"
 	"github.com/smgrushb/conv/internal/generics/collection/set"
 	"github.com/smgrushb/conv/internal/generics/gmap"
+	"github.com/smgrushb/conv/internal/generics/gptr"
 	"github.com/smgrushb/conv/internal/generics/gslice"
 	"github.com/smgrushb/conv/internal/generics/gvalue"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -47,6 +51,11 @@ type CustomConverterV2 interface {
 	Key() string
 }
 
+// CustomConverterWithOption 自定义转换器可选实现，需要读取配置（如时区）时优先使用
+type CustomConverterWithOption interface {
+	ConverterWithOption(option *StructOption) func(dPtr, sPtr unsafe.Pointer) bool
+}
+
 type StructOption struct {
 	Phase                int `json:"-"`
 	IgnorePrivateFields  bool
@@ -58,18 +67,38 @@ type StructOption struct {
 	UseMarshal           bool
 	SerializeToString    bool
 	StrBytesZeroCopy     bool
+	ReportErrors         bool
+	Strict               bool
+	Lenient              bool
+	DeepCopy             bool
 	TagName              string
 	PriorityTagName      string
 	TimeFormat           string
+	TimeUnit             time.Duration
+	TimeLocation         *time.Location `json:"-"`
 	MinUnix              *int64
 	MinUnixScene         MinUnixSceneType
 	NilValuePolicy       NilValuePolicy
+	ArrayLengthPolicy    ArrayLengthPolicy
+	CyclePolicy          CyclePolicy
+	MergeMode            MergeMode
+	SliceStrategy        SliceStrategy
+	SliceKey             string
+	EnumTrimPrefix       bool
+	EnumPrefixes         []string
+	EnumCaseInsensitive  bool
+	EnumUnknownPolicy    EnumUnknownPolicy
+	AnyResolver          protoregistry.MessageTypeResolver `json:"-"`
 	BannedFields         *set.Set[string]
 	WhiteListFields      *set.Set[string]
+	FieldMaskFields      *set.Set[string] // FieldMask的路径，按FieldMaskName归一化，不按层级拆分
 	AliasFields          map[string]string
 	NestedOption         map[string]*StructOption
-	CustomConv           []CustomConverter   `json:"-"`
-	CustomConvV2         []CustomConverterV2 `json:"-"`
+	CustomConv           []CustomConverter              `json:"-"`
+	CustomConvV2         []CustomConverterV2            `json:"-"`
+	Hooks                []Hook                         `json:"-"`
+	FieldConv            map[string][]CustomConverterV2 `json:"-"` // 字段级自定义转换器，不向下继承
+	fingerprint          string                         // 缓存的key，见key
 }
 
 func newOption() *StructOption {
@@ -77,8 +106,10 @@ func newOption() *StructOption {
 		StrBytesZeroCopy: true,
 		BannedFields:     set.New[string](),
 		WhiteListFields:  set.New[string](),
+		FieldMaskFields:  set.New[string](),
 		AliasFields:      make(map[string]string),
 		NestedOption:     make(map[string]*StructOption),
+		FieldConv:        make(map[string][]CustomConverterV2),
 	}
 }
 
@@ -86,6 +117,12 @@ func GetOption(phase int, opts ...Option) *StructOption {
 	if len(opts) == 0 {
 		return defaultStructOption()
 	}
+	// 单独使用预编译配置时直接使用其解析结果
+	if phase == 0 && len(opts) == 1 {
+		if compiled, ok := compiledOptions.Load(funcIdentity(opts[0])); ok {
+			return compiled.(*StructOption)
+		}
+	}
 	opt := newOption()
 	for _, v := range opts {
 		if phase == 0 {
@@ -110,6 +147,28 @@ func GetOption(phase int, opts ...Option) *StructOption {
 	return opt.parse()
 }
 
+// compiledOptions CompileOption返回的函数 => 预编译的配置，按函数值指向的闭包对象区分，见funcIdentity
+var compiledOptions sync.Map // unsafe.Pointer => *StructOption
+
+// CompileOption 预先解析配置并计算key，返回的配置单独使用时共享同一个解析结果，
+// 与其他配置一起使用或用于两阶段转换时依次应用原配置重新解析。
+// 解析结果常驻内存且只读：切片去掉多余容量，追加时不会写入共享的底层数组，其余字段构建转换器时只读不写
+func CompileOption(opts ...Option) Option {
+	compiled := GetOption(0, opts...)
+	compiled.EnumPrefixes = clip(compiled.EnumPrefixes)
+	compiled.CustomConv = clip(compiled.CustomConv)
+	compiled.CustomConvV2 = clip(compiled.CustomConvV2)
+	compiled.Hooks = clip(compiled.Hooks)
+	compiled.key()
+	apply := func(o *StructOption) {
+		for _, v := range opts {
+			v(o)
+		}
+	}
+	compiledOptions.Store(funcIdentity(apply), compiled)
+	return apply
+}
+
 func (o *StructOption) Clone() *StructOption {
 	if o == nil {
 		return nil
@@ -125,18 +184,37 @@ func (o *StructOption) Clone() *StructOption {
 		UseMarshal:           o.UseMarshal,
 		SerializeToString:    o.SerializeToString,
 		StrBytesZeroCopy:     o.StrBytesZeroCopy,
+		ReportErrors:         o.ReportErrors,
+		Strict:               o.Strict,
+		Lenient:              o.Lenient,
+		DeepCopy:             o.DeepCopy,
 		TagName:              o.TagName,
 		PriorityTagName:      o.PriorityTagName,
 		TimeFormat:           o.TimeFormat,
+		TimeUnit:             o.TimeUnit,
+		TimeLocation:         o.TimeLocation,
 		MinUnix:              o.MinUnix,
 		MinUnixScene:         o.MinUnixScene,
 		NilValuePolicy:       o.NilValuePolicy,
+		ArrayLengthPolicy:    o.ArrayLengthPolicy,
+		CyclePolicy:          o.CyclePolicy,
+		MergeMode:            o.MergeMode,
+		SliceStrategy:        o.SliceStrategy,
+		SliceKey:             o.SliceKey,
+		EnumTrimPrefix:       o.EnumTrimPrefix,
+		EnumPrefixes:         clip(o.EnumPrefixes),
+		EnumCaseInsensitive:  o.EnumCaseInsensitive,
+		EnumUnknownPolicy:    o.EnumUnknownPolicy,
+		AnyResolver:          o.AnyResolver,
 		BannedFields:         o.BannedFields.Clone(),
 		WhiteListFields:      o.WhiteListFields.Clone(),
+		FieldMaskFields:      o.FieldMaskFields.Clone(),
 		AliasFields:          gmap.Clone(o.AliasFields),
 		NestedOption:         gmap.CloneBy(o.NestedOption, (*StructOption).Clone),
-		CustomConv:           o.CustomConv,
-		CustomConvV2:         o.CustomConvV2,
+		CustomConv:           clip(o.CustomConv),
+		CustomConvV2:         clip(o.CustomConvV2),
+		Hooks:                clip(o.Hooks),
+		FieldConv:            gmap.CloneBy(o.FieldConv, clip[[]CustomConverterV2]),
 	}
 }
 
@@ -146,16 +224,39 @@ func (o *StructOption) inherit(parent *StructOption) *StructOption {
 	o.IgnoreEmptyFields = parent.IgnoreEmptyFields
 	o.IgnoreTag = parent.IgnoreTag
 	o.IgnoreFunc = parent.IgnoreFunc
+	o.ReportErrors = parent.ReportErrors
+	o.Strict = parent.Strict
+	o.Lenient = parent.Lenient
+	o.DeepCopy = parent.DeepCopy
+	o.StrBytesZeroCopy = parent.StrBytesZeroCopy
 	o.TagName = parent.TagName
 	o.PriorityTagName = parent.PriorityTagName
 	o.TimeFormat = parent.TimeFormat
+	o.TimeUnit = parent.TimeUnit
+	o.TimeLocation = parent.TimeLocation
 	o.MinUnix = parent.MinUnix
 	o.MinUnixScene = parent.MinUnixScene
-	o.CustomConv = parent.CustomConv
-	o.CustomConvV2 = parent.CustomConvV2
+	o.ArrayLengthPolicy = parent.ArrayLengthPolicy
+	o.CyclePolicy = parent.CyclePolicy
+	o.MergeMode = parent.MergeMode
+	o.SliceStrategy = parent.SliceStrategy
+	o.SliceKey = parent.SliceKey
+	o.EnumTrimPrefix = parent.EnumTrimPrefix
+	o.EnumPrefixes = clip(parent.EnumPrefixes)
+	o.EnumCaseInsensitive = parent.EnumCaseInsensitive
+	o.EnumUnknownPolicy = parent.EnumUnknownPolicy
+	o.AnyResolver = parent.AnyResolver
+	o.CustomConv = clip(parent.CustomConv)
+	o.CustomConvV2 = clip(parent.CustomConvV2)
+	o.Hooks = clip(parent.Hooks)
 	return o
 }
 
+// clip 共享的切片去掉多余容量，之后追加时重新分配，不会写入原切片的底层数组
+func clip[S ~[]T, T any](s S) S {
+	return s[:len(s):len(s)]
+}
+
 func (o *StructOption) parse() *StructOption {
 	o.BannedFields.ForEach(func(s string) {
 		if first, second, ok := split(s); ok {
@@ -188,20 +289,117 @@ func (o *StructOption) parse() *StructOption {
 			nest.AliasFields[second] = a
 		}
 	}
+	for f, c := range o.FieldConv {
+		if first, second, ok := split(f); ok {
+			nest, ok := o.NestedOption[first]
+			if !ok {
+				nest = newOption().inherit(o)
+				o.NestedOption[first] = nest
+			}
+			nest.FieldConv[second] = append(nest.FieldConv[second], c...)
+		}
+	}
 	for _, nest := range o.NestedOption {
 		nest.parse()
 	}
 	return o
 }
 
+// key 配置的指纹，首次计算后缓存：配置在GetOption返回后只读，构建转换器时（加锁前）即已计算
 func (o *StructOption) key() string {
 	if o == nil {
 		return ""
 	}
-	convKey := strings.Join(gslice.Sort(gslice.Map(o.CustomConv, CustomConverter.Key)), ";")
-	convV2Key := strings.Join(gslice.Sort(gslice.Map(o.CustomConvV2, CustomConverterV2.Key)), ";")
-	bs, _ := encoder.Encode(o, encoder.SortMapKeys)
-	return fmt.Sprintf("%s%s%s", string(bs), convKey, convV2Key)
+	if len(o.fingerprint) == 0 {
+		o.fingerprint = string(o.appendKey(make([]byte, 0, 128)))
+	}
+	return o.fingerprint
+}
+
+// appendKey 逐个字段写入指纹，StructOption新增字段时需同时加入
+func (o *StructOption) appendKey(b []byte) []byte {
+	for _, v := range [...]bool{o.IgnorePrivateFields, o.IncludePrivateFields, o.IgnoreEmptyFields, o.IgnoreTag, o.IgnoreFunc,
+		o.UseStrings, o.UseMarshal, o.SerializeToString, o.StrBytesZeroCopy, o.ReportErrors, o.Strict, o.Lenient, o.DeepCopy,
+		o.EnumTrimPrefix, o.EnumCaseInsensitive, o.MinUnix != nil} {
+		b = strconv.AppendBool(b, v)
+		b = append(b, ',')
+	}
+	for _, v := range [...]int64{int64(o.TimeUnit), int64(o.MinUnixScene), int64(o.NilValuePolicy), int64(o.ArrayLengthPolicy),
+		int64(o.CyclePolicy), int64(o.MergeMode), int64(o.SliceStrategy), int64(o.EnumUnknownPolicy), gptr.Indirect(o.MinUnix)} {
+		b = strconv.AppendInt(b, v, 10)
+		b = append(b, ',')
+	}
+	for _, v := range [...]string{o.TagName, o.PriorityTagName, o.TimeFormat, o.SliceKey} {
+		b = strconv.AppendQuote(b, v)
+	}
+	if o.TimeLocation != nil {
+		b = append(b, "[loc:"...)
+		b = append(b, o.TimeLocation.String()...)
+		b = append(b, ']')
+	}
+	if o.AnyResolver != nil {
+		b = append(b, "[resolver:"...)
+		b = append(b, identity(o.AnyResolver)...)
+		b = append(b, ']')
+	}
+	b = appendStrings(append(b, "[prefix:"...), o.EnumPrefixes)
+	for _, s := range [...]*set.Set[string]{o.BannedFields, o.WhiteListFields, o.FieldMaskFields} {
+		var fields []string
+		s.ForEach(func(f string) { fields = append(fields, f) })
+		b = appendStrings(append(b, "[set:"...), gslice.Sort(fields))
+	}
+	b = append(b, "[alias:"...)
+	for _, k := range gslice.Sort(gmap.Keys(o.AliasFields)) {
+		b = strconv.AppendQuote(b, k)
+		b = strconv.AppendQuote(append(b, ':'), o.AliasFields[k])
+	}
+	b = append(b, ']')
+	// 同优先级的自定义转换器按注册顺序生效，key不能排序
+	b = appendStrings(append(b, "[conv:"...), gslice.Map(o.CustomConv, CustomConverter.Key))
+	b = appendStrings(append(b, "[convV2:"...), gslice.Map(o.CustomConvV2, CustomConverterV2.Key))
+	b = appendStrings(append(b, "[hook:"...), gslice.Map(o.Hooks, Hook.Key))
+	for _, k := range gslice.Sort(gmap.Keys(o.FieldConv)) {
+		b = strconv.AppendQuote(append(b, "[field:"...), k)
+		b = appendStrings(b, gslice.Map(o.FieldConv[k], CustomConverterV2.Key))
+	}
+	for _, k := range gslice.Sort(gmap.Keys(o.NestedOption)) {
+		b = strconv.AppendQuote(append(b, "[nest:"...), k)
+		b = append(o.NestedOption[k].appendKey(b), ']')
+	}
+	return b
+}
+
+func appendStrings(b []byte, s []string) []byte {
+	for _, v := range s {
+		b = strconv.AppendQuote(b, v)
+	}
+	return append(b, ']')
+}
+
+// wholeStruct 同类型结构体是否整体复制，合并模式或指定了FieldMask时需要逐个字段转换
+func (o *StructOption) wholeStruct() bool {
+	return o == nil || o.MergeMode == MergeModeOverwrite && !o.filtering()
+}
+
+// filtering 是否指定了FieldMask
+func (o *StructOption) filtering() bool {
+	return o != nil && !o.FieldMaskFields.Empty()
+}
+
+// identity 指针按地址区分，其他按值区分
+func identity(v any) string {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		return fmt.Sprintf("%T:%#x", v, rv.Pointer())
+	}
+	return fmt.Sprintf("%T:%v", v, v)
+}
+
+// fieldConv 字段级自定义转换器
+func (o *StructOption) fieldConv(name string) []CustomConverterV2 {
+	if o == nil {
+		return nil
+	}
+	return o.FieldConv[name]
 }
 
 func split(s string) (first, second string, ok bool) {
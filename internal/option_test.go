@@ -0,0 +1,85 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// 克隆后的字段级转换器追加时不能写入原配置或其他克隆的底层数组
+func TestCloneFieldConv(t *testing.T) {
+	itoa := NewFuncConverter(func(i int) (string, error) { return strconv.Itoa(i), nil })
+	x := NewFuncConverter(func(i int) (int64, error) { return int64(i), nil })
+	y := NewFuncConverter(func(i int) (float64, error) { return float64(i), nil })
+	o := newOption()
+	o.FieldConv["a"] = append(make([]CustomConverterV2, 0, 4), itoa)
+	c1, c2 := o.Clone(), o.Clone()
+	c1.FieldConv["a"] = append(c1.FieldConv["a"], x)
+	c2.FieldConv["a"] = append(c2.FieldConv["a"], y)
+	if len(o.FieldConv["a"]) != 1 || c1.FieldConv["a"][1] != x || c2.FieldConv["a"][1] != y {
+		t.Fatalf("field converters shared between clones")
+	}
+	o.CustomConvV2 = append(make([]CustomConverterV2, 0, 4), itoa)
+	n := newOption().inherit(o)
+	n.CustomConvV2 = append(n.CustomConvV2, x)
+	if o.CustomConvV2[:2][1] == x || len(o.CustomConvV2) != 1 {
+		t.Fatalf("custom converters shared with parent")
+	}
+}
+
+// 预编译配置单独使用时共享解析结果，与其他配置一起使用或两阶段转换时重新解析
+func TestCompileOption(t *testing.T) {
+	opts := []Option{
+		func(o *StructOption) { o.ReportErrors = true },
+		func(o *StructOption) { o.BannedFields.Add("A.B") },
+	}
+	compiled := CompileOption(opts...)
+	shared := GetOption(0, compiled)
+	if GetOption(0, compiled) != shared {
+		t.Fatalf("compiled option parsed again")
+	}
+	if shared.key() != GetOption(0, opts...).key() {
+		t.Fatalf("compiled key differs from parsed key")
+	}
+	if cap(shared.CustomConvV2) != len(shared.CustomConvV2) {
+		t.Fatalf("compiled slices keep spare capacity")
+	}
+	for _, o := range []*StructOption{
+		GetOption(0, compiled, func(o *StructOption) { o.Strict = true }),
+		GetOption(1, compiled),
+	} {
+		if o == shared || !o.ReportErrors || !o.NestedOption["A"].BannedFields.Contains("B") {
+			t.Fatalf("compiled option not applied: %+v", o)
+		}
+	}
+}
+
+// 配置不同时key不同
+func TestOptionKey(t *testing.T) {
+	zero := int64(0)
+	cases := []Option{
+		func(o *StructOption) {},
+		func(o *StructOption) { o.Strict = true },
+		func(o *StructOption) { o.MinUnix = &zero },
+		func(o *StructOption) { o.TimeFormat = "2006" },
+		func(o *StructOption) { o.TimeLocation = time.UTC },
+		func(o *StructOption) { o.BannedFields.Add("A") },
+		func(o *StructOption) { o.WhiteListFields.Add("A") },
+		func(o *StructOption) { o.AliasFields["A"] = "B" },
+		func(o *StructOption) { o.AliasFields["A.B"] = "C" },
+		func(o *StructOption) { o.EnumPrefixes = []string{"A"} },
+	}
+	keys := make(map[string]int)
+	for i, opt := range cases {
+		key := GetOption(0, opt).key()
+		if j, ok := keys[key]; ok {
+			t.Fatalf("case %d and %d share key %s", j, i, key)
+		}
+		keys[key] = i
+	}
+}
@@ -0,0 +1,664 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package internal
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// protoConverter 至少一侧是proto消息的结构体转换，消息一侧通过protoreflect按字段描述读写，不依赖生成代码的Go字段
+type protoConverter struct {
+	*convertType
+	fields  []*protoFieldConverter
+	hooks   *structHooks
+	scratch reflect.Type // 消息一侧单个值的临时空间，每次转换分配一次，为nil时不需要
+	dMsg    bool
+	sMsg    bool
+	enable  bool // 兜底
+}
+
+func newProtoConverter(typ *convertType) converter {
+	if typ.dstTyp == typ.srcTyp && typ.option.wholeStruct() {
+		// 同类型与普通结构体一致，整体按位复制（浅拷贝），DeepCopy时由deepCopyConverter处理
+		return newStructConverter(typ)
+	}
+	c := &protoConverter{convertType: typ, hooks: newStructHooks(typ), dMsg: isProtoStruct(typ.dstTyp), sMsg: isProtoStruct(typ.srcTyp)}
+	key := typ.key()
+	// 先预注册进去，不然循环依赖下会循环解析
+	createdConverters[key] = &Converter{convertType: typ, converter: c}
+	matches, _ := matchProtoFields(typ)
+	for _, m := range matches {
+		if m.status != FieldStatusOK {
+			continue
+		}
+		if fc := m.newConverter(typ.option); fc != nil {
+			c.fields = append(c.fields, fc)
+		}
+	}
+	if len(c.fields) == 0 {
+		// 把预注册的内容删了
+		delete(createdConverters, key)
+		return nil
+	}
+	c.initScratch()
+	c.enable = true
+	return c
+}
+
+// initScratch 为消息一侧的单个值（非重复、非map字段）分配临时空间中的位置，有presence的标量另外存放指向的值
+func (c *protoConverter) initScratch() {
+	var fields []reflect.StructField
+	slot := func(t reflect.Type) int {
+		fields = append(fields, reflect.StructField{Name: "F" + strconv.Itoa(len(fields)), Type: t})
+		return len(fields) - 1
+	}
+	type slots struct{ s, sElem, d int }
+	index := make([]slots, len(c.fields))
+	for i, f := range c.fields {
+		if f.sField.single() {
+			index[i].s = slot(f.sField.goTyp)
+			if f.sField.scalarPtr() {
+				index[i].sElem = slot(f.sField.goTyp.Elem())
+			}
+		}
+		if f.dField.single() {
+			index[i].d = slot(f.dField.goTyp)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+	c.scratch = reflect.StructOf(fields)
+	for i, f := range c.fields {
+		if f.sField.single() {
+			f.sSlot = c.scratch.Field(index[i].s).Offset
+			if f.sField.scalarPtr() {
+				f.sElemSlot = c.scratch.Field(index[i].sElem).Offset
+			}
+		}
+		if f.dField.single() {
+			f.dSlot = c.scratch.Field(index[i].d).Offset
+		}
+	}
+}
+
+func (c *protoConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	if !c.enable {
+		return false
+	}
+	if c.hooks == nil {
+		return c.convertFields(dPtr, sPtr, cs)
+	}
+	if !c.hooks.runBefore(c.convertType, dPtr, sPtr, cs) {
+		return false
+	}
+	converted := c.convertFields(dPtr, sPtr, cs)
+	return c.hooks.runAfter(c.convertType, dPtr, sPtr, cs) && converted
+}
+
+func (c *protoConverter) convertFields(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	var dm, sm protoreflect.Message
+	if c.dMsg {
+		dm = protoMessageAt(c.dstTyp, dPtr).ProtoReflect()
+	}
+	if c.sMsg {
+		sm = protoMessageAt(c.srcTyp, sPtr).ProtoReflect()
+	}
+	var scratch unsafe.Pointer
+	if c.scratch != nil {
+		scratch = reflect.New(c.scratch).UnsafePointer()
+	}
+	var hasConverted bool
+	for _, f := range c.fields {
+		hits := cs.enterMask(f.converter.dName, f.converter.dRepeated)
+		converted := f.convert(dm, sm, dPtr, sPtr, scratch, cs)
+		if hits >= 0 {
+			cs.leaveMask(hits, converted && f.populated(dm, dPtr))
+		}
+		hasConverted = converted || hasConverted
+	}
+	return hasConverted
+}
+
+// protoFieldConverter 一个字段的转换，消息一侧读写为Go类型的值，再用fieldConverter与另一侧转换：
+// 单个值直接在protoreflect.Value与临时空间之间读写，重复字段和map字段构建中间值
+type protoFieldConverter struct {
+	dField    *protoField // 为nil时目标是Go结构体
+	sField    *protoField // 为nil时源是Go结构体
+	converter *fieldConverter
+	sSlot     uintptr // 源的单个值在临时空间中的偏移
+	sElemSlot uintptr // 源为有presence的标量时指向的值的偏移
+	dSlot     uintptr // 目标的单个值在临时空间中的偏移
+	appendTo  bool    // 重复字段追加到已有元素后
+	skipZero  bool    // 结果为零值时不写入
+}
+
+func (f *protoFieldConverter) convert(dm, sm protoreflect.Message, dPtr, sPtr, scratch unsafe.Pointer, cs *convState) bool {
+	if f.sField != nil {
+		if sPtr = f.read(sm, scratch); sPtr == nil {
+			return false
+		}
+	}
+	if f.dField == nil {
+		return f.converter.convertFrom(dPtr, sPtr, cs)
+	}
+	var tmp unsafe.Pointer
+	if f.dField.single() {
+		tmp = unsafe.Pointer(uintptr(scratch) + f.dSlot)
+	} else {
+		tmp = reflect.New(f.dField.goTyp).UnsafePointer()
+	}
+	if !f.converter.convertFrom(tmp, sPtr, cs) {
+		return false
+	}
+	if f.skipZero && isZeroElem(reflect.NewAt(f.dField.goTyp, tmp).Elem()) {
+		return false
+	}
+	f.write(dm, tmp)
+	return true
+}
+
+// read 读取源字段，返回指向Go类型的值的指针，有presence且未设置时返回nil
+func (f *protoFieldConverter) read(m protoreflect.Message, scratch unsafe.Pointer) unsafe.Pointer {
+	sf := f.sField
+	if sf.fd.HasPresence() && !m.Has(sf.fd) {
+		return nil
+	}
+	if !sf.single() {
+		return unsafe.Pointer(sf.get(m).UnsafeAddr())
+	}
+	p := unsafe.Pointer(uintptr(scratch) + f.sSlot)
+	if sf.scalarPtr() {
+		e := unsafe.Pointer(uintptr(scratch) + f.sElemSlot)
+		sf.load(m.Get(sf.fd), e)
+		*(*unsafe.Pointer)(p) = e
+	} else {
+		sf.load(m.Get(sf.fd), p)
+	}
+	return p
+}
+
+// write 写入目标字段，nil的指针清空字段（oneof分支不清空，以免清掉其他分支）
+func (f *protoFieldConverter) write(m protoreflect.Message, p unsafe.Pointer) {
+	df := f.dField
+	if !df.single() {
+		df.set(m, reflect.NewAt(df.goTyp, p).Elem(), f.appendTo)
+		return
+	}
+	if df.goTyp.Kind() == reflect.Pointer && *(*unsafe.Pointer)(p) == nil {
+		if !df.oneof {
+			m.Clear(df.fd)
+		}
+		return
+	}
+	if df.scalarPtr() {
+		p = *(*unsafe.Pointer)(p)
+	}
+	m.Set(df.fd, df.store(p))
+}
+
+// populated 目标字段是否有值，目标为消息时按Has判断
+func (f *protoFieldConverter) populated(dm protoreflect.Message, dPtr unsafe.Pointer) bool {
+	if f.dField == nil {
+		return f.converter.populated(dPtr)
+	}
+	return dm.Has(f.dField.fd)
+}
+
+// protoField proto消息的字段，按对应的Go类型读写：标量、枚举类型、*Msg，有presence的标量为指针，重复字段为切片，map字段为map
+type protoField struct {
+	fd    protoreflect.FieldDescriptor
+	goTyp reflect.Type
+	oneof bool // 属于oneof（不含proto3 optional）
+	// 单个值直接读写，有presence的标量读写指向的值
+	load  func(v protoreflect.Value, p unsafe.Pointer)
+	store func(p unsafe.Pointer) protoreflect.Value
+}
+
+func newProtoField(m protoreflect.Message, fd protoreflect.FieldDescriptor) *protoField {
+	f := &protoField{fd: fd, oneof: fd.ContainingOneof() != nil && !fd.ContainingOneof().IsSynthetic()}
+	switch {
+	case fd.IsList():
+		f.goTyp = reflect.SliceOf(protoElemType(fd, func() protoreflect.Message { return m.NewField(fd).List().NewElement().Message() }))
+	case fd.IsMap():
+		f.goTyp = reflect.MapOf(protoElemType(fd.MapKey(), nil), protoElemType(fd.MapValue(), func() protoreflect.Message { return m.NewField(fd).Map().NewValue().Message() }))
+	default:
+		f.goTyp = protoElemType(fd, func() protoreflect.Message { return m.NewField(fd).Message() })
+		f.load, f.store = protoAccessOf(fd.Kind(), f.goTyp)
+		if fd.HasPresence() && fd.Message() == nil {
+			f.goTyp = reflect.PointerTo(f.goTyp)
+		}
+	}
+	return f
+}
+
+// single 单个值，不是重复字段和map字段，f为nil时返回false
+func (f *protoField) single() bool {
+	return f != nil && !f.fd.IsList() && !f.fd.IsMap()
+}
+
+// scalarPtr 有presence的标量，Go类型为指针
+func (f *protoField) scalarPtr() bool {
+	return f.single() && f.fd.Message() == nil && f.goTyp.Kind() == reflect.Pointer
+}
+
+// protoAccessOf 单个值与Go类型的值直接互转，枚举按int32读写，消息按*Msg读写
+func protoAccessOf(kind protoreflect.Kind, goTyp reflect.Type) (func(protoreflect.Value, unsafe.Pointer), func(unsafe.Pointer) protoreflect.Value) {
+	switch kind {
+	case protoreflect.BoolKind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*bool)(p) = v.Bool() },
+			func(p unsafe.Pointer) protoreflect.Value { return protoreflect.ValueOfBool(*(*bool)(p)) }
+	case protoreflect.EnumKind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*int32)(p) = int32(v.Enum()) },
+			func(p unsafe.Pointer) protoreflect.Value {
+				return protoreflect.ValueOfEnum(protoreflect.EnumNumber(*(*int32)(p)))
+			}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*int32)(p) = int32(v.Int()) },
+			func(p unsafe.Pointer) protoreflect.Value { return protoreflect.ValueOfInt32(*(*int32)(p)) }
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*int64)(p) = v.Int() },
+			func(p unsafe.Pointer) protoreflect.Value { return protoreflect.ValueOfInt64(*(*int64)(p)) }
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*uint32)(p) = uint32(v.Uint()) },
+			func(p unsafe.Pointer) protoreflect.Value { return protoreflect.ValueOfUint32(*(*uint32)(p)) }
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*uint64)(p) = v.Uint() },
+			func(p unsafe.Pointer) protoreflect.Value { return protoreflect.ValueOfUint64(*(*uint64)(p)) }
+	case protoreflect.FloatKind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*float32)(p) = float32(v.Float()) },
+			func(p unsafe.Pointer) protoreflect.Value { return protoreflect.ValueOfFloat32(*(*float32)(p)) }
+	case protoreflect.DoubleKind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*float64)(p) = v.Float() },
+			func(p unsafe.Pointer) protoreflect.Value { return protoreflect.ValueOfFloat64(*(*float64)(p)) }
+	case protoreflect.StringKind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*string)(p) = v.String() },
+			func(p unsafe.Pointer) protoreflect.Value { return protoreflect.ValueOfString(*(*string)(p)) }
+	case protoreflect.BytesKind:
+		return func(v protoreflect.Value, p unsafe.Pointer) { *(*[]byte)(p) = v.Bytes() },
+			func(p unsafe.Pointer) protoreflect.Value { return protoreflect.ValueOfBytes(*(*[]byte)(p)) }
+	}
+	msgTyp := goTyp.Elem()
+	return func(v protoreflect.Value, p unsafe.Pointer) {
+			*(*unsafe.Pointer)(p) = reflect.ValueOf(v.Message().Interface()).UnsafePointer()
+		},
+		func(p unsafe.Pointer) protoreflect.Value {
+			return protoreflect.ValueOfMessage(protoMessageAt(msgTyp, *(*unsafe.Pointer)(p)).ProtoReflect())
+		}
+}
+
+var (
+	protoKindTypes = map[protoreflect.Kind]reflect.Type{
+		protoreflect.BoolKind:     ReflectType[bool](),
+		protoreflect.Int32Kind:    ReflectType[int32](),
+		protoreflect.Sint32Kind:   ReflectType[int32](),
+		protoreflect.Sfixed32Kind: ReflectType[int32](),
+		protoreflect.Int64Kind:    ReflectType[int64](),
+		protoreflect.Sint64Kind:   ReflectType[int64](),
+		protoreflect.Sfixed64Kind: ReflectType[int64](),
+		protoreflect.Uint32Kind:   ReflectType[uint32](),
+		protoreflect.Fixed32Kind:  ReflectType[uint32](),
+		protoreflect.Uint64Kind:   ReflectType[uint64](),
+		protoreflect.Fixed64Kind:  ReflectType[uint64](),
+		protoreflect.FloatKind:    ReflectType[float32](),
+		protoreflect.DoubleKind:   ReflectType[float64](),
+		protoreflect.StringKind:   ReflectType[string](),
+		protoreflect.BytesKind:    ReflectType[[]byte](),
+	}
+	int32Type = ReflectType[int32]()
+)
+
+// protoElemType 单个值的Go类型，枚举在注册表中找不到时按int32处理
+func protoElemType(fd protoreflect.FieldDescriptor, newMessage func() protoreflect.Message) reflect.Type {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if et, err := protoregistry.GlobalTypes.FindEnumByName(fd.Enum().FullName()); err == nil {
+			return reflect.TypeOf(et.New(0))
+		}
+		return int32Type
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return reflect.TypeOf(newMessage().Interface())
+	}
+	return protoKindTypes[fd.Kind()]
+}
+
+// get 读取重复字段或map字段，构建为切片或map
+func (f *protoField) get(m protoreflect.Message) reflect.Value {
+	fd := f.fd
+	v := reflect.New(f.goTyp).Elem()
+	if fd.IsList() {
+		if l := m.Get(fd).List(); l.Len() > 0 {
+			s := reflect.MakeSlice(f.goTyp, l.Len(), l.Len())
+			for i := 0; i < l.Len(); i++ {
+				s.Index(i).Set(goValueOf(fd, l.Get(i), f.goTyp.Elem()))
+			}
+			v.Set(s)
+		}
+	} else if mp := m.Get(fd).Map(); mp.Len() > 0 {
+		res := reflect.MakeMapWithSize(f.goTyp, mp.Len())
+		mp.Range(func(k protoreflect.MapKey, val protoreflect.Value) bool {
+			res.SetMapIndex(goValueOf(fd.MapKey(), k.Value(), f.goTyp.Key()), goValueOf(fd.MapValue(), val, f.goTyp.Elem()))
+			return true
+		})
+		v.Set(res)
+	}
+	return v
+}
+
+// set 写入重复字段或map字段
+func (f *protoField) set(m protoreflect.Message, v reflect.Value, appendTo bool) {
+	fd := f.fd
+	if fd.IsList() {
+		if !appendTo {
+			m.Clear(fd)
+		}
+		if v.Len() == 0 {
+			return
+		}
+		l := m.Mutable(fd).List()
+		for i := 0; i < v.Len(); i++ {
+			if e := v.Index(i); e.Kind() == reflect.Pointer && e.IsNil() {
+				l.Append(l.NewElement())
+			} else {
+				l.Append(protoValueOf(fd, e))
+			}
+		}
+		return
+	}
+	m.Clear(fd)
+	if v.Len() == 0 {
+		return
+	}
+	mp := m.Mutable(fd).Map()
+	for iter := v.MapRange(); iter.Next(); {
+		k, e := protoValueOf(fd.MapKey(), iter.Key()).MapKey(), iter.Value()
+		if e.Kind() == reflect.Pointer && e.IsNil() {
+			mp.Set(k, mp.NewValue())
+		} else {
+			mp.Set(k, protoValueOf(fd.MapValue(), e))
+		}
+	}
+}
+
+func goValueOf(fd protoreflect.FieldDescriptor, v protoreflect.Value, t reflect.Type) reflect.Value {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return reflect.ValueOf(int32(v.Enum())).Convert(t)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return reflect.ValueOf(v.Message().Interface())
+	}
+	return reflect.ValueOf(v.Interface()).Convert(t)
+}
+
+func protoValueOf(fd protoreflect.FieldDescriptor, v reflect.Value) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v.Int()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoreflect.ValueOfMessage(v.Interface().(proto.Message).ProtoReflect())
+	}
+	return protoreflect.ValueOf(v.Convert(protoKindTypes[fd.Kind()]).Interface())
+}
+
+// protoFields 消息的全部字段，按proto字段名、json_name、Go字段名都可以找到
+type protoFields struct {
+	list   []*protoField
+	byName map[string]*protoField
+	oneofs map[string]bool // oneof的proto名和Go字段名
+}
+
+func newProtoFields(t reflect.Type) *protoFields {
+	m := protoMessageAt(t, reflect.New(t).UnsafePointer()).ProtoReflect()
+	fds := m.Descriptor().Fields()
+	pf := &protoFields{byName: make(map[string]*protoField, fds.Len()*3), oneofs: make(map[string]bool)}
+	for i := 0; i < fds.Len(); i++ {
+		f := newProtoField(m, fds.Get(i))
+		pf.list = append(pf.list, f)
+		if od := f.fd.ContainingOneof(); f.oneof {
+			pf.oneofs[string(od.Name())] = true
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if sf := t.Field(i); len(sf.Tag.Get("protobuf_oneof")) > 0 {
+			pf.oneofs[sf.Name] = true
+		}
+	}
+	// 同名时proto字段名优先
+	for _, f := range pf.list {
+		pf.byName[string(f.fd.Name())] = f
+	}
+	for _, f := range pf.list {
+		for _, name := range protoFieldNames(f.fd)[1:] {
+			if _, ok := pf.byName[name]; !ok {
+				pf.byName[name] = f
+			}
+		}
+	}
+	return pf
+}
+
+// protoFieldNames proto字段名、json_name、Go字段名，去重
+func protoFieldNames(fd protoreflect.FieldDescriptor) []string {
+	names := []string{string(fd.Name())}
+	for _, name := range []string{fd.JSONName(), goCamelCase(string(fd.Name()))} {
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func contains(names []string, name string) bool {
+	for _, v := range names {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// goCamelCase 与protoc-gen-go生成的Go字段名一致，如user_id => UserId
+func goCamelCase(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' && i == 0:
+			sb.WriteByte('X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case '0' <= c && c <= '9':
+			sb.WriteByte(c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			sb.WriteByte(c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				sb.WriteByte(s[i+1])
+			}
+		}
+	}
+	return sb.String()
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+// protoMatch 目标字段与源字段的匹配结果，消息一侧为field，Go结构体一侧为item
+type protoMatch struct {
+	name   string // 匹配用的名字
+	dName  string
+	dField *protoField
+	dItem  *structItem
+	sField *protoField
+	sItem  *structItem
+	status FieldStatus
+}
+
+// matchProtoFields 按目标字段的顺序匹配源字段，unused为未被匹配的源字段。
+// 消息字段按proto字段名、json_name、Go字段名匹配，一侧的oneof与另一侧同名的结构体字段互相展开
+func matchProtoFields(typ *convertType) (matches []protoMatch, unused []string) {
+	option := typ.option
+	var dFields, sFields *protoFields
+	if isProtoStruct(typ.dstTyp) {
+		dFields = newProtoFields(typ.dstTyp)
+	}
+	if isProtoStruct(typ.srcTyp) {
+		sFields = newProtoFields(typ.srcTyp)
+	}
+	// 源为Go结构体
+	var sItems map[string]*structItem
+	var sItemIndex []*structItem
+	if sFields == nil {
+		sItems = make(map[string]*structItem)
+		sItemIndex = extractFields(typ.srcTyp, option, sItems, nil)
+		sItemIndex = expandOneofUnion(sItemIndex, dFields.oneofs, option, sItems)
+		if option == nil || !option.IgnoreFunc {
+			extractMethods(typ.srcTyp, option, sItems)
+		}
+	}
+	// 目标的每个字段可以有多个名字
+	type dst struct {
+		names []string
+		field *protoField
+		item  *structItem
+	}
+	var dsts []dst
+	if dFields != nil {
+		for _, f := range dFields.list {
+			dsts = append(dsts, dst{names: protoFieldNames(f.fd), field: f})
+		}
+	} else {
+		var oneofs map[string]bool
+		if sFields != nil {
+			oneofs = sFields.oneofs
+		}
+		for _, item := range expandOneofUnion(extractFields(typ.dstTyp, option, nil, nil), oneofs, option, nil) {
+			dsts = append(dsts, dst{names: []string{item.name}, item: item})
+		}
+	}
+	used := make(map[any]bool)
+	for _, d := range dsts {
+		m := protoMatch{name: d.names[0], dField: d.field, dItem: d.item, status: FieldStatusOK}
+		if d.item != nil {
+			m.dName = d.item.filedName
+		} else {
+			m.dName = string(d.field.fd.Name())
+		}
+		lookup := d.names
+		for _, name := range d.names {
+			if alias, ok := option.aliasField(name); ok {
+				lookup = []string{alias}
+				break
+			}
+		}
+		for _, name := range lookup {
+			if sFields != nil {
+				if m.sField = sFields.byName[name]; m.sField != nil {
+					m.name = name
+					used[m.sField] = true
+					break
+				}
+			} else if m.sItem = sItems[name]; m.sItem != nil {
+				m.name = name
+				used[m.sItem] = true
+				break
+			}
+		}
+		switch {
+		case option != nil && option.BannedFields != nil && containsAny(option.BannedFields.Contains, d.names):
+			m.status = FieldStatusBanned
+		case m.sField == nil && m.sItem == nil:
+			m.status = FieldStatusUnmatched
+		case option != nil && option.WhiteListFields != nil && !option.WhiteListFields.Empty() && !option.WhiteListFields.Contains(m.name), option.maskExcluded(m.name):
+			m.status = FieldStatusFiltered
+		}
+		matches = append(matches, m)
+	}
+	if sFields != nil {
+		for _, f := range sFields.list {
+			if !used[f] {
+				unused = append(unused, string(f.fd.Name()))
+			}
+		}
+	} else {
+		for _, item := range sItemIndex {
+			if !used[item] {
+				unused = append(unused, item.displayName())
+			}
+		}
+	}
+	return
+}
+
+func containsAny(contains func(string) bool, names []string) bool {
+	for _, name := range names {
+		if contains(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// newConverter 消息一侧用Go类型的临时值代替结构体字段，无法转换时返回nil
+func (m protoMatch) newConverter(option *StructOption) *protoFieldConverter {
+	var nestOption *StructOption
+	if option != nil && option.NestedOption != nil {
+		nestOption = option.NestedOption[m.name]
+	}
+	if nestOption == nil {
+		nestOption = option
+	}
+	nestOption = option.maskNested(nestOption, m.name)
+	df, sf := m.dItem, m.sItem
+	if m.dField != nil {
+		df = m.dField.item(m.dName)
+	}
+	if m.sField != nil {
+		sf = m.sField.item(string(m.sField.fd.Name()))
+	}
+	fc := newFieldConverter(*df, *sf, nestOption, option.fieldConv(m.name))
+	if fc == nil {
+		return nil
+	}
+	return &protoFieldConverter{
+		dField:    m.dField,
+		sField:    m.sField,
+		converter: fc,
+		appendTo:  nestOption != nil && nestOption.SliceStrategy == SliceStrategyAppend,
+		// 平铺的字段写入oneof分支时零值视为未设置，指针只有nil视为未设置（nil不写入分支）
+		skipZero: m.dField != nil && m.dField.oneof && (m.sField == nil && m.sItem.typ.Kind() != reflect.Pointer || m.sField != nil && !m.sField.fd.HasPresence()),
+	}
+}
+
+// item 临时值对应的字段
+func (f *protoField) item(name string) *structItem {
+	return &structItem{itemType: typeField, name: string(f.fd.Name()), filedName: name, typ: f.goTyp, offset: []uintptr{0}}
+}
+
+// isZeroElem 零值或指向零值的指针
+func isZeroElem(v reflect.Value) bool {
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v.IsZero()
+}
+
+// isProtoStruct 结构体类型的指针是否实现了proto.Message
+func isProtoStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && isProtoMessage(reflect.PointerTo(t))
+}
+
+func protoMessageAt(t reflect.Type, p unsafe.Pointer) proto.Message {
+	return reflect.NewAt(t, p).Interface().(proto.Message)
+}
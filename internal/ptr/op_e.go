@@ -0,0 +1,205 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package ptr
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// ErrOutOfRange 严格模式下数值转换溢出或丢失精度
+var ErrOutOfRange = errors.New("value out of range")
+
+// CvtOpE 带错误返回的转换，非严格模式下转换结果与对应的CvtOp一致，严格模式下失败时不写入目标
+type CvtOpE func(unsafe.Pointer, unsafe.Pointer) error
+
+type number interface {
+	int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | uintptr | float32 | float64
+}
+
+// 非严格模式：bitSize与gen_op中的CvtOp一致（int、uint按64位解析后截断），只额外返回解析错误
+var cvtOpEs = map[convertKind]CvtOpE{
+	{reflect.String, reflect.Int}:     cvtStringIntE[int](64),
+	{reflect.String, reflect.Int8}:    cvtStringIntE[int8](8),
+	{reflect.String, reflect.Int16}:   cvtStringIntE[int16](16),
+	{reflect.String, reflect.Int32}:   cvtStringIntE[int32](32),
+	{reflect.String, reflect.Int64}:   cvtStringIntE[int64](64),
+	{reflect.String, reflect.Uint}:    cvtStringUintE[uint](64),
+	{reflect.String, reflect.Uint8}:   cvtStringUintE[uint8](8),
+	{reflect.String, reflect.Uint16}:  cvtStringUintE[uint16](16),
+	{reflect.String, reflect.Uint32}:  cvtStringUintE[uint32](32),
+	{reflect.String, reflect.Uint64}:  cvtStringUintE[uint64](64),
+	{reflect.String, reflect.Float32}: cvtStringFloatE[float32](32),
+	{reflect.String, reflect.Float64}: cvtStringFloatE[float64](64),
+	{reflect.String, reflect.Bool}:    cvtStringBoolE,
+}
+
+// 严格模式：string解析失败、数值溢出或丢失精度时报错
+var strictCvtOpEs = map[convertKind]CvtOpE{
+	{reflect.String, reflect.Int}:      strictStringIntE[int](strconv.IntSize),
+	{reflect.String, reflect.Int8}:     strictStringIntE[int8](8),
+	{reflect.String, reflect.Int16}:    strictStringIntE[int16](16),
+	{reflect.String, reflect.Int32}:    strictStringIntE[int32](32),
+	{reflect.String, reflect.Int64}:    strictStringIntE[int64](64),
+	{reflect.String, reflect.Uint}:     strictStringUintE[uint](strconv.IntSize),
+	{reflect.String, reflect.Uint8}:    strictStringUintE[uint8](8),
+	{reflect.String, reflect.Uint16}:   strictStringUintE[uint16](16),
+	{reflect.String, reflect.Uint32}:   strictStringUintE[uint32](32),
+	{reflect.String, reflect.Uint64}:   strictStringUintE[uint64](64),
+	{reflect.String, reflect.Float32}:  strictStringFloatE[float32](32),
+	{reflect.String, reflect.Float64}:  strictStringFloatE[float64](64),
+	{reflect.String, reflect.Bool}:     strictStringBoolE,
+	{reflect.Float64, reflect.Float32}: strictFloat64Float32E,
+}
+
+func init() {
+	registerStrictNumber[int](reflect.Int)
+	registerStrictNumber[int8](reflect.Int8)
+	registerStrictNumber[int16](reflect.Int16)
+	registerStrictNumber[int32](reflect.Int32)
+	registerStrictNumber[int64](reflect.Int64)
+	registerStrictNumber[uint](reflect.Uint)
+	registerStrictNumber[uint8](reflect.Uint8)
+	registerStrictNumber[uint16](reflect.Uint16)
+	registerStrictNumber[uint32](reflect.Uint32)
+	registerStrictNumber[uint64](reflect.Uint64)
+	registerStrictNumber[uintptr](reflect.Uintptr)
+	registerStrictNumber[float32](reflect.Float32)
+	registerStrictNumber[float64](reflect.Float64)
+}
+
+func registerStrictNumber[S number](sk reflect.Kind) {
+	register := func(dk reflect.Kind, op CvtOpE) {
+		// 同类型及float32转float64不会丢失精度，float64转float32单独处理
+		if key := (convertKind{sk, dk}); sk != dk && key != (convertKind{reflect.Float32, reflect.Float64}) && strictCvtOpEs[key] == nil {
+			strictCvtOpEs[key] = op
+		}
+	}
+	register(reflect.Int, strictNumberE[S, int])
+	register(reflect.Int8, strictNumberE[S, int8])
+	register(reflect.Int16, strictNumberE[S, int16])
+	register(reflect.Int32, strictNumberE[S, int32])
+	register(reflect.Int64, strictNumberE[S, int64])
+	register(reflect.Uint, strictNumberE[S, uint])
+	register(reflect.Uint8, strictNumberE[S, uint8])
+	register(reflect.Uint16, strictNumberE[S, uint16])
+	register(reflect.Uint32, strictNumberE[S, uint32])
+	register(reflect.Uint64, strictNumberE[S, uint64])
+	register(reflect.Uintptr, strictNumberE[S, uintptr])
+	register(reflect.Float32, strictNumberE[S, float32])
+	register(reflect.Float64, strictNumberE[S, float64])
+}
+
+// GetCvtOpE 获取可能失败的基础类型转换，非严格模式下仅有string转数值/bool
+func GetCvtOpE(st, dt reflect.Type, strict, lenient bool) CvtOpE {
+	key := convertKind{st.Kind(), dt.Kind()}
+	switch {
+	case lenient && strict && strictLenientCvtOpEs[key] != nil:
+		return strictLenientCvtOpEs[key]
+	case lenient && lenientCvtOpEs[key] != nil:
+		return lenientCvtOpEs[key]
+	case strict:
+		return strictCvtOpEs[key]
+	}
+	return cvtOpEs[key]
+}
+
+func cvtStringIntE[T int | int8 | int16 | int32 | int64](bitSize int) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		i64, err := strconv.ParseInt(*(*string)(sPtr), 10, bitSize)
+		*(*T)(dPtr) = T(i64)
+		return err
+	}
+}
+
+func cvtStringUintE[T uint | uint8 | uint16 | uint32 | uint64](bitSize int) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		ui64, err := strconv.ParseUint(*(*string)(sPtr), 10, bitSize)
+		*(*T)(dPtr) = T(ui64)
+		return err
+	}
+}
+
+func cvtStringFloatE[T float32 | float64](bitSize int) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		f64, err := strconv.ParseFloat(*(*string)(sPtr), bitSize)
+		*(*T)(dPtr) = T(f64)
+		return err
+	}
+}
+
+func cvtStringBoolE(sPtr, dPtr unsafe.Pointer) error {
+	b, err := strconv.ParseBool(*(*string)(sPtr))
+	*(*bool)(dPtr) = b
+	return err
+}
+
+// strictNumberE 转换后能原样转回且符号不变才视为成功
+func strictNumberE[S, D number](sPtr, dPtr unsafe.Pointer) error {
+	s := *(*S)(sPtr)
+	d := D(s)
+	if S(d) != s || (s < 0) != (d < 0) {
+		return ErrOutOfRange
+	}
+	*(*D)(dPtr) = d
+	return nil
+}
+
+// strictFloat64Float32E 浮点数之间只检查溢出，不检查精度
+func strictFloat64Float32E(sPtr, dPtr unsafe.Pointer) error {
+	f64 := *(*float64)(sPtr)
+	f32 := float32(f64)
+	if !math.IsInf(f64, 0) && math.IsInf(float64(f32), 0) {
+		return ErrOutOfRange
+	}
+	*(*float32)(dPtr) = f32
+	return nil
+}
+
+func strictStringIntE[T int | int8 | int16 | int32 | int64](bitSize int) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		i64, err := strconv.ParseInt(*(*string)(sPtr), 10, bitSize)
+		if err != nil {
+			return err
+		}
+		*(*T)(dPtr) = T(i64)
+		return nil
+	}
+}
+
+func strictStringUintE[T uint | uint8 | uint16 | uint32 | uint64](bitSize int) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		ui64, err := strconv.ParseUint(*(*string)(sPtr), 10, bitSize)
+		if err != nil {
+			return err
+		}
+		*(*T)(dPtr) = T(ui64)
+		return nil
+	}
+}
+
+func strictStringFloatE[T float32 | float64](bitSize int) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		f64, err := strconv.ParseFloat(*(*string)(sPtr), bitSize)
+		if err != nil {
+			return err
+		}
+		*(*T)(dPtr) = T(f64)
+		return nil
+	}
+}
+
+func strictStringBoolE(sPtr, dPtr unsafe.Pointer) error {
+	b, err := strconv.ParseBool(*(*string)(sPtr))
+	if err != nil {
+		return err
+	}
+	*(*bool)(dPtr) = b
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package ptr
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// 宽松模式：去除首尾空白，支持0x/0o/0b前缀、_和千分位,分隔符，整数支持小数部分为0的浮点数
+var (
+	lenientCvtOpEs       = make(map[convertKind]CvtOpE)
+	strictLenientCvtOpEs = make(map[convertKind]CvtOpE)
+)
+
+func init() {
+	for _, strict := range []bool{false, true} {
+		ops := lenientCvtOpEs
+		if strict {
+			ops = strictLenientCvtOpEs
+		}
+		ops[convertKind{reflect.String, reflect.Int}] = lenientIntE[int](strconv.IntSize, strict)
+		ops[convertKind{reflect.String, reflect.Int8}] = lenientIntE[int8](8, strict)
+		ops[convertKind{reflect.String, reflect.Int16}] = lenientIntE[int16](16, strict)
+		ops[convertKind{reflect.String, reflect.Int32}] = lenientIntE[int32](32, strict)
+		ops[convertKind{reflect.String, reflect.Int64}] = lenientIntE[int64](64, strict)
+		ops[convertKind{reflect.String, reflect.Uint}] = lenientUintE[uint](strconv.IntSize, strict)
+		ops[convertKind{reflect.String, reflect.Uint8}] = lenientUintE[uint8](8, strict)
+		ops[convertKind{reflect.String, reflect.Uint16}] = lenientUintE[uint16](16, strict)
+		ops[convertKind{reflect.String, reflect.Uint32}] = lenientUintE[uint32](32, strict)
+		ops[convertKind{reflect.String, reflect.Uint64}] = lenientUintE[uint64](64, strict)
+		ops[convertKind{reflect.String, reflect.Float32}] = lenientFloatE[float32](32, strict)
+		ops[convertKind{reflect.String, reflect.Float64}] = lenientFloatE[float64](64, strict)
+		ops[convertKind{reflect.String, reflect.Bool}] = lenientBoolE(strict)
+	}
+}
+
+// ParseIntLenient 宽松解析整数
+func ParseIntLenient(s string, bitSize int) (int64, error) {
+	str, base := normalizeNumber(s)
+	i64, err := strconv.ParseInt(str, base, bitSize)
+	if err == nil || base != 10 || !isSyntaxErr(err) {
+		return i64, withNum(err, s)
+	}
+	// 小数部分为0的浮点数
+	f, ferr := strconv.ParseFloat(str, 64)
+	if ferr != nil || f != math.Trunc(f) {
+		return 0, withNum(err, s)
+	}
+	if limit := math.Ldexp(1, bitSize-1); f < -limit || f >= limit {
+		if f < 0 {
+			return -1 << (bitSize - 1), &strconv.NumError{Func: "ParseInt", Num: s, Err: strconv.ErrRange}
+		}
+		return 1<<(bitSize-1) - 1, &strconv.NumError{Func: "ParseInt", Num: s, Err: strconv.ErrRange}
+	}
+	return int64(f), nil
+}
+
+// ParseUintLenient 宽松解析无符号整数
+func ParseUintLenient(s string, bitSize int) (uint64, error) {
+	str, base := normalizeNumber(s)
+	ui64, err := strconv.ParseUint(str, base, bitSize)
+	if err == nil || base != 10 || !isSyntaxErr(err) {
+		return ui64, withNum(err, s)
+	}
+	// 小数部分为0的浮点数
+	f, ferr := strconv.ParseFloat(str, 64)
+	if ferr != nil || f != math.Trunc(f) || f < 0 {
+		return 0, withNum(err, s)
+	}
+	if f >= math.Ldexp(1, bitSize) {
+		return 1<<bitSize - 1, &strconv.NumError{Func: "ParseUint", Num: s, Err: strconv.ErrRange}
+	}
+	return uint64(f), nil
+}
+
+// ParseFloatLenient 宽松解析浮点数
+func ParseFloatLenient(s string, bitSize int) (float64, error) {
+	str, base := normalizeNumber(s)
+	f, err := strconv.ParseFloat(str, bitSize)
+	if err == nil || base == 10 || !isSyntaxErr(err) {
+		return f, withNum(err, s)
+	}
+	// 0x/0o/0b前缀的整数
+	if i64, ierr := strconv.ParseInt(str, 0, 64); ierr == nil {
+		return float64(i64), nil
+	}
+	return 0, withNum(err, s)
+}
+
+// normalizeNumber 去除首尾空白和分隔符，返回解析使用的进制，只有带进制前缀时才使用0，避免前导0被当作八进制
+func normalizeNumber(s string) (string, int) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, ",_") {
+		s = strings.NewReplacer(",", "", "_", "").Replace(s)
+	}
+	unsigned := strings.TrimLeft(s, "+-")
+	if len(unsigned) > 2 && unsigned[0] == '0' {
+		switch unsigned[1] {
+		case 'x', 'X', 'o', 'O', 'b', 'B':
+			return s, 0
+		}
+	}
+	return s, 10
+}
+
+func isSyntaxErr(err error) bool {
+	ne, ok := err.(*strconv.NumError)
+	return ok && ne.Err == strconv.ErrSyntax
+}
+
+// withNum 错误信息中使用原始字符串
+func withNum(err error, s string) error {
+	if ne, ok := err.(*strconv.NumError); ok {
+		ne.Num = s
+	}
+	return err
+}
+
+func lenientIntE[T int | int8 | int16 | int32 | int64](bitSize int, strict bool) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		i64, err := ParseIntLenient(*(*string)(sPtr), bitSize)
+		if err != nil && strict {
+			return err
+		}
+		*(*T)(dPtr) = T(i64)
+		return err
+	}
+}
+
+func lenientUintE[T uint | uint8 | uint16 | uint32 | uint64](bitSize int, strict bool) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		ui64, err := ParseUintLenient(*(*string)(sPtr), bitSize)
+		if err != nil && strict {
+			return err
+		}
+		*(*T)(dPtr) = T(ui64)
+		return err
+	}
+}
+
+func lenientFloatE[T float32 | float64](bitSize int, strict bool) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		f64, err := ParseFloatLenient(*(*string)(sPtr), bitSize)
+		if err != nil && strict {
+			return err
+		}
+		*(*T)(dPtr) = T(f64)
+		return err
+	}
+}
+
+func lenientBoolE(strict bool) CvtOpE {
+	return func(sPtr, dPtr unsafe.Pointer) error {
+		b, err := strconv.ParseBool(strings.TrimSpace(*(*string)(sPtr)))
+		if err != nil && strict {
+			return err
+		}
+		*(*bool)(dPtr) = b
+		return err
+	}
+}
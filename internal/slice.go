@@ -20,6 +20,10 @@ type sliceConverter struct {
 	*elemConverter
 	dElemSize uintptr
 	sElemSize uintptr
+	strategy  SliceStrategy
+	dKeyIndex []int // 按键合并时键字段在目标元素中的位置
+	sKeyIndex []int // 按键合并时键字段在源元素中的位置
+	keyType   reflect.Type
 	enable    bool // 兜底
 }
 
@@ -29,7 +33,14 @@ func newSliceConverter(typ *convertType) converter {
 		dElemSize:   typ.dstTyp.Elem().Size(),
 		sElemSize:   typ.srcTyp.Elem().Size(),
 	}
-	if c.enable = typ.srcTyp == typ.dstTyp; c.enable {
+	if typ.option != nil {
+		c.strategy = typ.option.SliceStrategy
+		if c.strategy == SliceStrategyKey && !c.initKey(typ.option.SliceKey) {
+			c.strategy = SliceStrategyReplace
+		}
+	}
+	// 替换策略下同类型直接按位复制，其余策略或指定了FieldMask时需要逐个元素转换
+	if c.enable = typ.srcTyp == typ.dstTyp && c.strategy == SliceStrategyReplace && !typ.option.filtering(); c.enable {
 		return c
 	}
 	key := typ.key()
@@ -45,10 +56,18 @@ func newSliceConverter(typ *convertType) converter {
 	return nil
 }
 
-func (s *sliceConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (s *sliceConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
 	if !s.enable {
 		return false
 	}
+	switch s.strategy {
+	case SliceStrategyAppend:
+		return s.appendConvert(dPtr, sPtr, cs)
+	case SliceStrategyIndex:
+		return s.indexConvert(dPtr, sPtr, cs)
+	case SliceStrategyKey:
+		return s.keyConvert(dPtr, sPtr, cs)
+	}
 	dSlice, sSlice := (*unsafeheader.SliceHeader)(dPtr), (*unsafeheader.SliceHeader)(sPtr)
 	length := sSlice.Len
 	dSlice.Len = length
@@ -57,16 +76,136 @@ func (s *sliceConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
 		newVal := reflect.MakeSlice(s.dstTyp, length, length)
 		dv.Set(newVal)
 	}
-	if s.srcTyp == s.dstTyp {
+	if s.elemConverter == nil {
 		ptr.Copy(dSlice.Data, sSlice.Data, uintptr(length)*s.sElemSize)
 		return true
 	}
 	for dOffset, sOffset, i := uintptr(0), uintptr(0), 0; i < length; i++ {
 		dElemPtr := unsafe.Pointer(uintptr(dSlice.Data) + dOffset)
 		sElemPtr := unsafe.Pointer(uintptr(sSlice.Data) + sOffset)
-		s.elemConverter.convert(dElemPtr, sElemPtr)
+		cs.pushIndex(i)
+		s.elemConverter.convert(dElemPtr, sElemPtr, cs)
+		cs.pop()
 		dOffset += s.dElemSize
 		sOffset += s.sElemSize
 	}
 	return true
 }
+
+// initKey 源和目标元素（可为指针）都是结构体且都有可比较的导出键字段时才按键合并
+func (s *sliceConverter) initKey(field string) bool {
+	dElemTyp, _ := referDeep(s.dstTyp.Elem())
+	sElemTyp, _ := referDeep(s.srcTyp.Elem())
+	if len(field) == 0 || dElemTyp.Kind() != reflect.Struct || sElemTyp.Kind() != reflect.Struct {
+		return false
+	}
+	df, dOk := dElemTyp.FieldByName(field)
+	sf, sOk := sElemTyp.FieldByName(field)
+	if !dOk || !sOk || !df.IsExported() || !sf.IsExported() || !df.Type.Comparable() {
+		return false
+	}
+	// 键类型一致，或同为数值、同为字符串
+	dk, sk := df.Type.Kind(), sf.Type.Kind()
+	if df.Type != sf.Type && !(isNumberKind(dk) && isNumberKind(sk)) && !(dk == reflect.String && sk == reflect.String) {
+		return false
+	}
+	s.dKeyIndex, s.sKeyIndex, s.keyType = df.Index, sf.Index, df.Type
+	return true
+}
+
+// grow 扩展目标切片到length并返回新增部分的起始下标，保留已有元素，新增元素为零值
+func (s *sliceConverter) grow(dPtr unsafe.Pointer, length int) int {
+	dv := reflect.NewAt(s.dstTyp, dPtr).Elem()
+	oldLen := dv.Len()
+	if dv.IsNil() || dv.Cap() < length {
+		newVal := reflect.MakeSlice(s.dstTyp, length, length)
+		reflect.Copy(newVal, dv)
+		dv.Set(newVal)
+		return oldLen
+	}
+	dv.SetLen(length)
+	zero := reflect.Zero(s.dstTyp.Elem())
+	for i := oldLen; i < length; i++ {
+		dv.Index(i).Set(zero)
+	}
+	return oldLen
+}
+
+func (s *sliceConverter) elemConvert(dPtr, sPtr unsafe.Pointer, di, si int, cs *convState) {
+	dElemPtr := unsafe.Pointer(uintptr((*unsafeheader.SliceHeader)(dPtr).Data) + uintptr(di)*s.dElemSize)
+	sElemPtr := unsafe.Pointer(uintptr((*unsafeheader.SliceHeader)(sPtr).Data) + uintptr(si)*s.sElemSize)
+	cs.pushIndex(si)
+	s.elemConverter.convert(dElemPtr, sElemPtr, cs)
+	cs.pop()
+}
+
+func (s *sliceConverter) appendConvert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	sLen := (*unsafeheader.SliceHeader)(sPtr).Len
+	start := s.grow(dPtr, (*unsafeheader.SliceHeader)(dPtr).Len+sLen)
+	for i := 0; i < sLen; i++ {
+		s.elemConvert(dPtr, sPtr, start+i, i, cs)
+	}
+	return true
+}
+
+func (s *sliceConverter) indexConvert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	sLen := (*unsafeheader.SliceHeader)(sPtr).Len
+	if dLen := (*unsafeheader.SliceHeader)(dPtr).Len; dLen < sLen || (*unsafeheader.SliceHeader)(dPtr).Data == nil {
+		s.grow(dPtr, sLen)
+	}
+	for i := 0; i < sLen; i++ {
+		s.elemConvert(dPtr, sPtr, i, i, cs)
+	}
+	return true
+}
+
+func (s *sliceConverter) keyConvert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	dv := reflect.NewAt(s.dstTyp, dPtr).Elem()
+	sv := reflect.NewAt(s.srcTyp, sPtr).Elem()
+	index := make(map[any]int, dv.Len())
+	for i := 0; i < dv.Len(); i++ {
+		if k, ok := sliceElemKey(dv.Index(i), s.dKeyIndex); ok {
+			if _, exist := index[k.Interface()]; !exist {
+				index[k.Interface()] = i
+			}
+		}
+	}
+	var unmatched []int
+	for i := 0; i < sv.Len(); i++ {
+		k, ok := sliceElemKey(sv.Index(i), s.sKeyIndex)
+		if !ok {
+			unmatched = append(unmatched, i)
+			continue
+		}
+		if k.Type() != s.keyType {
+			k = k.Convert(s.keyType)
+		}
+		if di, exist := index[k.Interface()]; exist {
+			s.elemConvert(dPtr, sPtr, di, i, cs)
+		} else {
+			unmatched = append(unmatched, i)
+		}
+	}
+	if len(unmatched) > 0 {
+		start := s.grow(dPtr, dv.Len()+len(unmatched))
+		for j, i := range unmatched {
+			s.elemConvert(dPtr, sPtr, start+j, i, cs)
+		}
+	}
+	return true
+}
+
+// sliceElemKey 获取元素的键字段，元素为nil或键为零值时返回false
+func sliceElemKey(v reflect.Value, index []int) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return zeroReflectValue, false
+		}
+		v = v.Elem()
+	}
+	k, err := v.FieldByIndexErr(index)
+	if err != nil || k.IsZero() {
+		return zeroReflectValue, false
+	}
+	return k, true
+}
@@ -23,7 +23,7 @@ type stringsConverter struct {
 	*convertType
 }
 
-func (s *stringsConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (s *stringsConverter) convert(dPtr, sPtr unsafe.Pointer, _ *convState) bool {
 	if strings, ok := reflect.NewAt(s.srcTyp, sPtr).Interface().(fmt.Stringer); ok {
 		*(*string)(dPtr) = strings.String()
 		return true
@@ -35,12 +35,14 @@ type marshalJsonConverter struct {
 	*convertType
 }
 
-func (m *marshalJsonConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (m *marshalJsonConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
 	if marshaler, ok := reflect.NewAt(m.srcTyp, sPtr).Interface().(json.Marshaler); ok {
-		if bs, err := marshaler.MarshalJSON(); err == nil {
-			*(*string)(dPtr) = string(bs)
-			return true
+		bs, err := marshaler.MarshalJSON()
+		if err != nil {
+			return cs.fail(m.dstTyp, m.srcTyp, err)
 		}
+		*(*string)(dPtr) = string(bs)
+		return true
 	}
 	return false
 }
@@ -50,7 +52,7 @@ type serializeConverter struct {
 	nilValuePolicy NilValuePolicy
 }
 
-func (s *serializeConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (s *serializeConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
 	p := reflect.NewAt(s.srcTyp, sPtr)
 	if p.IsNil() {
 		if s.nilValuePolicy == NilValuePolicyIgnore {
@@ -72,11 +74,12 @@ func (s *serializeConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
 			e = reflect.New(s.srcTyp).Elem()
 		}
 	}
-	if str, err := sonic.MarshalString(e.Interface()); err == nil {
-		*(*string)(dPtr) = str
-		return true
+	str, err := sonic.MarshalString(e.Interface())
+	if err != nil {
+		return cs.fail(s.dstTyp, s.srcTyp, err)
 	}
-	return false
+	*(*string)(dPtr) = str
+	return true
 }
 
 func newStringsConverter(typ *convertType) converter {
@@ -9,6 +9,7 @@
 package internal
 
 import (
+	"fmt"
 	"github.com/smgrushb/conv/internal/generics/collection/set"
 	"github.com/smgrushb/conv/internal/generics/gptr"
 	"github.com/smgrushb/conv/internal/generics/gslice"
@@ -17,6 +18,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"reflect"
 	"strings"
+	"time"
 	"unicode"
 	"unsafe"
 )
@@ -34,24 +36,22 @@ type structConverter struct {
 	*convertType
 	fieldConverters []converter
 	size            uintptr
+	hooks           *structHooks
 	convMap         bool
+	fromMap         bool
 	enable          bool // 兜底
 }
 
 func newStructConverter(typ *convertType) converter {
-	if typ.srcTyp == typ.dstTyp {
-		return &structConverter{convertType: typ, size: typ.srcTyp.Size(), enable: true}
+	// 合并模式或指定了FieldMask时需要逐个字段判断
+	if typ.srcTyp == typ.dstTyp && typ.option.wholeStruct() {
+		return &structConverter{convertType: typ, size: typ.srcTyp.Size(), hooks: newStructHooks(typ), enable: true}
 	}
-	c := &structConverter{convertType: typ}
+	c := &structConverter{convertType: typ, hooks: newStructHooks(typ)}
 	key := typ.key()
 	// 先预注册进去，不然循环依赖下会循环解析
 	createdConverters[key] = &Converter{convertType: typ, converter: c}
-	sFields := make(map[string]*structItem)
-	extractFields(typ.srcTyp, typ.option, sFields, nil)
-	if typ.option == nil || !typ.option.IgnoreFunc {
-		extractMethods(typ.srcTyp, typ.option, sFields)
-	}
-	dFieldIndex := extractFields(typ.dstTyp, typ.option, nil, nil)
+	sFields, _, dFieldIndex := extractStructFields(typ)
 	if typ.option != nil {
 		dFieldIndex = filterField(dFieldIndex, typ.option.BannedFields)
 		dFieldIndex = aliasField(dFieldIndex, typ.option.AliasFields)
@@ -59,7 +59,7 @@ func newStructConverter(typ *convertType) converter {
 	fieldConverters := make([]converter, 0, len(dFieldIndex))
 	for _, df := range dFieldIndex {
 		if sf, ok := sFields[df.name]; ok {
-			if typ.option != nil && !typ.option.WhiteListFields.Empty() && !typ.option.WhiteListFields.Contains(sf.name) {
+			if typ.option != nil && !typ.option.WhiteListFields.Empty() && !typ.option.WhiteListFields.Contains(sf.name) || typ.option.maskExcluded(sf.name) {
 				continue
 			}
 			var nestOption *StructOption
@@ -69,7 +69,8 @@ func newStructConverter(typ *convertType) converter {
 			if nestOption == nil {
 				nestOption = typ.option
 			}
-			if fc := newFieldConverter(*df, *sf, nestOption); fc != nil {
+			nestOption = typ.option.maskNested(nestOption, sf.name)
+			if fc := newFieldConverter(*df, *sf, nestOption, typ.option.fieldConv(df.name)); fc != nil {
 				fieldConverters = append(fieldConverters, fc)
 			}
 		}
@@ -84,8 +85,21 @@ func newStructConverter(typ *convertType) converter {
 	return c
 }
 
+// extractStructFields 提取源字段（含方法）和目标字段，一侧的oneof与另一侧同名的结构体字段互相展开
+func extractStructFields(typ *convertType) (sFields map[string]*structItem, sFieldIndex, dFieldIndex []*structItem) {
+	sFields = make(map[string]*structItem)
+	sFieldIndex = extractFields(typ.srcTyp, typ.option, sFields, nil)
+	dFieldIndex = extractFields(typ.dstTyp, typ.option, nil, nil)
+	sFieldIndex = expandOneofUnion(sFieldIndex, oneofNames(dFieldIndex), typ.option, sFields)
+	dFieldIndex = expandOneofUnion(dFieldIndex, oneofNames(sFieldIndex), typ.option, nil)
+	if typ.option == nil || !typ.option.IgnoreFunc {
+		extractMethods(typ.srcTyp, typ.option, sFields)
+	}
+	return
+}
+
 func newStructMapConverter(typ *convertType, valueType reflect.Type) converter {
-	c := &structConverter{convertType: typ, convMap: true}
+	c := &structConverter{convertType: typ, hooks: newStructHooks(typ), convMap: true}
 	key := typ.key()
 	// 先预注册进去，不然循环依赖下会循环解析
 	createdConverters[key] = &Converter{convertType: typ, converter: c}
@@ -98,7 +112,7 @@ func newStructMapConverter(typ *convertType, valueType reflect.Type) converter {
 	}
 	fieldConverters := make([]converter, 0, len(sFieldIndex))
 	for _, sf := range sFieldIndex {
-		if typ.option != nil && !typ.option.WhiteListFields.Empty() && !typ.option.WhiteListFields.Contains(sf.name) {
+		if typ.option != nil && !typ.option.WhiteListFields.Empty() && !typ.option.WhiteListFields.Contains(sf.name) || typ.option.maskExcluded(sf.name) {
 			continue
 		}
 		var nestOption *StructOption
@@ -108,7 +122,46 @@ func newStructMapConverter(typ *convertType, valueType reflect.Type) converter {
 		if nestOption == nil {
 			nestOption = typ.option
 		}
-		if fc := newFieldMapConverter(valueType, *sf, nestOption); fc != nil {
+		nestOption = typ.option.maskNested(nestOption, sf.name)
+		if fc := newFieldMapConverter(valueType, *sf, nestOption, typ.option.fieldConv(sf.name)); fc != nil {
+			fieldConverters = append(fieldConverters, fc)
+		}
+	}
+	if len(fieldConverters) == 0 {
+		// 把预注册的内容删了
+		delete(createdConverters, key)
+		return nil
+	}
+	c.fieldConverters = fieldConverters
+	c.enable = true
+	return c
+}
+
+func newMapStructConverter(typ *convertType, valueType reflect.Type) converter {
+	c := &structConverter{convertType: typ, hooks: newStructHooks(typ), fromMap: true}
+	key := typ.key()
+	// 先预注册进去，不然循环依赖下会循环解析
+	createdConverters[key] = &Converter{convertType: typ, converter: c}
+	// 从map映射时源和目标无区别，直接banned和alias直接作用到目标上即可
+	dFieldIndex := extractFieldsOnMap(typ.dstTyp, typ.option, make(map[string]*structItem), nil)
+	if typ.option != nil {
+		dFieldIndex = filterField(dFieldIndex, typ.option.BannedFields)
+		dFieldIndex = aliasField(dFieldIndex, typ.option.AliasFields)
+	}
+	fieldConverters := make([]converter, 0, len(dFieldIndex))
+	for _, df := range dFieldIndex {
+		if typ.option != nil && !typ.option.WhiteListFields.Empty() && !typ.option.WhiteListFields.Contains(df.name) || typ.option.maskExcluded(df.name) {
+			continue
+		}
+		var nestOption *StructOption
+		if typ.option != nil && typ.option.NestedOption != nil {
+			nestOption = typ.option.NestedOption[df.name]
+		}
+		if nestOption == nil {
+			nestOption = typ.option
+		}
+		nestOption = typ.option.maskNested(nestOption, df.name)
+		if fc := newMapFieldConverter(*df, typ.srcTyp.Key(), valueType, nestOption, typ.option.fieldConv(df.name)); fc != nil {
 			fieldConverters = append(fieldConverters, fc)
 		}
 	}
@@ -147,83 +200,108 @@ func aliasField(fields []*structItem, aliasFields map[string]string) []*structIt
 	return fields
 }
 
-func (s *structConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (s *structConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
 	if !s.enable {
 		return false
 	}
-	if s.convMap {
-		return s.mapConvert(dPtr, sPtr)
+	if s.hooks == nil {
+		return s.convertFields(dPtr, sPtr, cs)
+	}
+	if !s.hooks.runBefore(s.convertType, dPtr, sPtr, cs) {
+		return false
+	}
+	converted := s.convertFields(dPtr, sPtr, cs)
+	return s.hooks.runAfter(s.convertType, dPtr, sPtr, cs) && converted
+}
 
+func (s *structConverter) convertFields(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	if s.convMap {
+		return s.mapConvert(dPtr, sPtr, cs)
+	}
+	if s.fromMap {
+		return s.fromMapConvert(dPtr, sPtr, cs)
 	}
-	if s.dstTyp == s.srcTyp {
+	if s.dstTyp == s.srcTyp && s.fieldConverters == nil {
 		ptr.Copy(dPtr, sPtr, s.size)
 		return true
 	}
 	var hasConverted bool
-fcLoop:
 	for _, v := range s.fieldConverters {
-		fc, ok := v.(*fieldConverter)
-		if !ok {
-			continue
+		if fc, ok := v.(*fieldConverter); ok {
+			hits := cs.enterMask(fc.dName, fc.dRepeated)
+			converted := fc.convertFrom(dPtr, sPtr, cs)
+			if hits >= 0 {
+				cs.leaveMask(hits, converted && fc.populated(dPtr))
+			}
+			hasConverted = converted || hasConverted
 		}
-		dAnonymousPtr := gslice.Or(fc.dAnonymousPtr)
-		if !dAnonymousPtr && !gslice.Or(fc.sAnonymousPtr) {
-			hasConverted = fc.convert(unsafe.Pointer(uintptr(dPtr)+gslice.Sum(fc.dOffset)), unsafe.Pointer(uintptr(sPtr)+gslice.Sum(fc.sOffset))) || hasConverted
-		} else {
-			fsPtr, fdPtr := unsafe.Pointer(uintptr(sPtr)+fc.sOffset[0]), unsafe.Pointer(uintptr(dPtr)+fc.dOffset[0])
-			sOffset, dOffset := fc.sOffset[1:], fc.dOffset[1:]
-			for i, isPtr := range fc.sAnonymousPtr {
-				if isPtr {
-					fsPtr = unsafe.Pointer(*((**int)(fsPtr)))
-					if fsPtr == nil {
-						if dAnonymousPtr {
-							*(**int)(fdPtr) = nil
-							continue fcLoop
-						}
-						if fc.converter.nilValuePolicy == NilValuePolicyIgnore {
-							continue fcLoop
-						}
-						fsPtr = fc.converter.sEmptyDereferValPtr
-						break
-					}
+	}
+	return hasConverted
+}
+
+// convertFrom 按偏移量分别定位源结构体和目标结构体中的字段并转换，源匿名指针字段为nil时按NilValuePolicy处理
+func (fc *fieldConverter) convertFrom(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	dAnonymousPtr := gslice.Or(fc.dAnonymousPtr)
+	if !dAnonymousPtr && !gslice.Or(fc.sAnonymousPtr) {
+		return fc.convert(unsafe.Pointer(uintptr(dPtr)+gslice.Sum(fc.dOffset)), unsafe.Pointer(uintptr(sPtr)+gslice.Sum(fc.sOffset)), cs)
+	}
+	fsPtr, fdPtr := unsafe.Pointer(uintptr(sPtr)+fc.sOffset[0]), unsafe.Pointer(uintptr(dPtr)+fc.dOffset[0])
+	sOffset := fc.sOffset[1:]
+	for i, isPtr := range fc.sAnonymousPtr {
+		if isPtr {
+			fsPtr = unsafe.Pointer(*((**int)(fsPtr)))
+			if fsPtr == nil {
+				if dAnonymousPtr {
+					*(**int)(fdPtr) = nil
+					return false
 				}
-				fsPtr = unsafe.Pointer(uintptr(fsPtr) + sOffset[i])
-			}
-			var i int
-			var dNil bool
-			for ; i < len(fc.dAnonymousPtr); i++ {
-				if fc.dAnonymousPtr[i] {
-					oldPtr := fdPtr
-					fdPtr = unsafe.Pointer(*((**int)(fdPtr)))
-					if dNil = fdPtr == nil; dNil {
-						fdPtr = oldPtr
-						break
-					}
+				if fc.converter.nilValuePolicy == NilValuePolicyIgnore {
+					return false
 				}
-				fdPtr = unsafe.Pointer(uintptr(fdPtr) + dOffset[i])
+				fsPtr = fc.converter.sEmptyDereferValPtr
+				break
 			}
-			if dNil {
-				v := unsafe.Pointer(uintptr(newValuePtr(fc.dStructType)) + dOffset[len(fc.dAnonymousPtr)-1])
-				if !fc.convert(v, fsPtr) {
-					continue fcLoop
-				}
-				hasConverted = true
-				for j := len(fc.dAnonymousPtr) - 1; j >= i; j-- {
-					v = unsafe.Pointer(uintptr(v) - dOffset[j])
-					if fc.dAnonymousPtr[j] {
-						v = unsafe.Pointer(gptr.Of(v))
-					}
-				}
-				*(**int)(fdPtr) = *(**int)(v)
-			} else {
-				hasConverted = fc.convert(fdPtr, fsPtr) || hasConverted
+		}
+		fsPtr = unsafe.Pointer(uintptr(fsPtr) + sOffset[i])
+	}
+	return convertToField(dPtr, fsPtr, fc.dAnonymousPtr, fc.dOffset, fc.dStructType, fc, cs)
+}
+
+// convertToField 按偏移量逐层定位目标字段并转换，目标匿名指针字段为nil时新建
+func convertToField(dPtr, fsPtr unsafe.Pointer, dAnonymousPtr []bool, dOffset []uintptr, dStructType reflect.Type, c converter, cs *convState) bool {
+	fdPtr := unsafe.Pointer(uintptr(dPtr) + dOffset[0])
+	dOffset = dOffset[1:]
+	var i int
+	var dNil bool
+	for ; i < len(dAnonymousPtr); i++ {
+		if dAnonymousPtr[i] {
+			oldPtr := fdPtr
+			fdPtr = unsafe.Pointer(*((**int)(fdPtr)))
+			if dNil = fdPtr == nil; dNil {
+				fdPtr = oldPtr
+				break
 			}
 		}
+		fdPtr = unsafe.Pointer(uintptr(fdPtr) + dOffset[i])
 	}
-	return hasConverted
+	if !dNil {
+		return c.convert(fdPtr, fsPtr, cs)
+	}
+	v := unsafe.Pointer(uintptr(newValuePtr(dStructType)) + dOffset[len(dAnonymousPtr)-1])
+	if !c.convert(v, fsPtr, cs) {
+		return false
+	}
+	for j := len(dAnonymousPtr) - 1; j >= i; j-- {
+		v = unsafe.Pointer(uintptr(v) - dOffset[j])
+		if dAnonymousPtr[j] {
+			v = unsafe.Pointer(gptr.Of(v))
+		}
+	}
+	*(**int)(fdPtr) = *(**int)(v)
+	return true
 }
 
-func (s *structConverter) mapConvert(dPtr, sPtr unsafe.Pointer) bool {
+func (s *structConverter) mapConvert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
 	dv := reflect.NewAt(s.convertType.dstTyp, dPtr).Elem()
 	if dv.IsNil() {
 		dv.Set(reflect.MakeMapWithSize(s.convertType.dstTyp, len(s.fieldConverters)))
@@ -253,17 +331,50 @@ fcLoop:
 			}
 			fsPtr = unsafe.Pointer(uintptr(fsPtr) + sOffset[i])
 		}
+		if fc.sOneofIface != nil {
+			if fsPtr = oneofValue(fc.sOneofIface, fc.sOneofWrapper, fsPtr); fsPtr == nil {
+				continue
+			}
+		}
 		if s.option.IgnoreEmptyFields && reflect.DeepEqual(reflect.NewAt(fc.converter.sDereferType, fsPtr).Elem().Interface(), reflect.New(fc.converter.sDereferType).Elem().Interface()) {
 			continue
 		}
+		if skipMerge(fc.mergeMode, fc.converter.sType, fsPtr) {
+			continue
+		}
 		dKey := reflect.ValueOf(fc.dName)
 		dVal := reflect.New(fc.dType).Elem()
-		hasConverted = fc.convert(unsafe.Pointer(dVal.UnsafeAddr()), fsPtr) || hasConverted
+		hasConverted = fc.convert(unsafe.Pointer(dVal.UnsafeAddr()), fsPtr, cs) || hasConverted
 		dv.SetMapIndex(dKey, dVal)
 	}
 	return hasConverted
 }
 
+func (s *structConverter) fromMapConvert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	sv := reflect.NewAt(s.convertType.srcTyp, sPtr).Elem()
+	if sv.Len() == 0 {
+		return false
+	}
+	var hasConverted bool
+	for _, v := range s.fieldConverters {
+		fc, ok := v.(*mapFieldConverter)
+		if !ok {
+			continue
+		}
+		val := sv.MapIndex(fc.sKey)
+		if !val.IsValid() {
+			continue
+		}
+		hits := cs.enterMask(fc.dName, fc.dRepeated)
+		converted := convertToField(dPtr, PtrOfAny(val), fc.dAnonymousPtr, fc.dOffset, fc.dStructType, fc, cs)
+		if hits >= 0 {
+			cs.leaveMask(hits, converted && populatedAt(dPtr, fc.dAnonymousPtr, fc.dOffset, fc.converter.dType))
+		}
+		hasConverted = converted || hasConverted
+	}
+	return hasConverted
+}
+
 type fieldConverter struct {
 	converter     *elemConverter
 	sType         structItemType
@@ -276,14 +387,29 @@ type fieldConverter struct {
 	dOffset       []uintptr
 	sOffset       []uintptr
 	dName         string
+	dFieldName    string
 	sName         string
 	sFieldName    string
+	sOneofIface   reflect.Type
+	sOneofWrapper reflect.Type
+	mergeMode     MergeMode
+	dRepeated     bool // 目标为切片、数组、map
 }
 
-func (f *fieldConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
+func (f *fieldConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	cs.pushField(f.dFieldName)
+	defer cs.pop()
 	switch f.sType {
 	case typeField:
-		return f.converter.convert(dPtr, sPtr)
+		if skipMerge(f.mergeMode, f.converter.sType, sPtr) {
+			return false
+		}
+		return f.converter.convert(dPtr, sPtr, cs)
+	case typeOneof:
+		if sPtr = oneofValue(f.sOneofIface, f.sOneofWrapper, sPtr); sPtr == nil || skipMerge(f.mergeMode, f.converter.sType, sPtr) {
+			return false
+		}
+		return f.converter.convert(dPtr, sPtr, cs)
 	case typeFieldMethod, typeMethod:
 		var method reflect.Value
 		if f.sType == typeFieldMethod {
@@ -303,22 +429,30 @@ func (f *fieldConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
 				}
 			case errorOut:
 				if !callback[1].IsNil() {
-					return false
+					return cs.fail(f.converter.dType, f.converter.sType, callback[1].Interface().(error))
 				}
 			}
-			return f.converter.convert(dPtr, vPtr)
+			if skipMerge(f.mergeMode, f.converter.sType, vPtr) {
+				return false
+			}
+			return f.converter.convert(dPtr, vPtr, cs)
 		}
 	}
 	return false
 }
 
-func newFieldConverter(df, sf structItem, option *StructOption) *fieldConverter {
-	format := gvalue.Valid(df.format, sf.format)
-	if len(format) > 0 {
-		option = gvalue.Safe(option.Clone())
-		option.TimeFormat = format
+func newFieldConverter(df, sf structItem, option *StructOption, custom []CustomConverterV2) *fieldConverter {
+	option, err := tagOption(option, gvalue.Valid(df.format, sf.format), gvalue.Valid(df.unit, sf.unit), gvalue.Valid(df.tz, sf.tz))
+	var ec *elemConverter
+	var ok bool
+	switch {
+	case err != nil:
+		ec, ok = newInvalidElemConverter(df.typ, sf.typ, err), true
+	case df.itemType == typeOneof:
+		ec, ok = newOneofElemConverter(df, sf, option, custom)
+	default:
+		ec, ok = newElemConverter(df.typ, sf.typ, option, custom...)
 	}
-	ec, ok := newElemConverter(df.typ, sf.typ, option)
 	if !ok {
 		return nil
 	}
@@ -333,29 +467,47 @@ func newFieldConverter(df, sf structItem, option *StructOption) *fieldConverter
 		dOffset:       df.offset,
 		sOffset:       sf.offset,
 		dName:         df.name,
+		dFieldName:    df.filedName,
 		sName:         sf.name,
 		sFieldName:    sf.filedName,
+		sOneofIface:   sf.oneofIface,
+		sOneofWrapper: sf.oneofWrapper,
+		mergeMode:     option.MergeMode,
+		dRepeated:     isRepeatedType(ec.dDereferType),
 	}
 }
 
+// populated 目标字段是否有值，dPtr指向目标结构体
+func (f *fieldConverter) populated(dPtr unsafe.Pointer) bool {
+	return populatedAt(dPtr, f.dAnonymousPtr, f.dOffset, f.converter.dType)
+}
+
 type fieldMapConverter struct {
 	converter     *elemConverter
 	sAnonymousPtr []bool
 	sOffset       []uintptr
 	dName         string
 	dType         reflect.Type
+	sOneofIface   reflect.Type
+	sOneofWrapper reflect.Type
+	mergeMode     MergeMode
 }
 
-func (f *fieldMapConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
-	return f.converter.convert(dPtr, sPtr)
+func (f *fieldMapConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	cs.pushKey(f.dName)
+	defer cs.pop()
+	return f.converter.convert(dPtr, sPtr, cs)
 }
 
-func newFieldMapConverter(valueType reflect.Type, sf structItem, option *StructOption) *fieldMapConverter {
-	if len(sf.format) > 0 {
-		option = gvalue.Safe(option.Clone())
-		option.TimeFormat = sf.format
+func newFieldMapConverter(valueType reflect.Type, sf structItem, option *StructOption, custom []CustomConverterV2) *fieldMapConverter {
+	option, err := tagOption(option, sf.format, sf.unit, sf.tz)
+	var ec *elemConverter
+	var ok bool
+	if err != nil {
+		ec, ok = newInvalidElemConverter(valueType, sf.typ, err), true
+	} else {
+		ec, ok = newElemConverter(valueType, sf.typ, option, custom...)
 	}
-	ec, ok := newElemConverter(valueType, sf.typ, option)
 	if !ok {
 		return nil
 	}
@@ -365,7 +517,126 @@ func newFieldMapConverter(valueType reflect.Type, sf structItem, option *StructO
 		sOffset:       sf.offset,
 		dName:         sf.name,
 		dType:         valueType,
+		sOneofIface:   sf.oneofIface,
+		sOneofWrapper: sf.oneofWrapper,
+		mergeMode:     option.MergeMode,
+	}
+}
+
+type mapFieldConverter struct {
+	converter     *elemConverter
+	dStructType   reflect.Type
+	dAnonymousPtr []bool
+	dOffset       []uintptr
+	dName         string
+	dFieldName    string
+	sKey          reflect.Value
+	mergeMode     MergeMode
+	dRepeated     bool // 目标为切片、数组、map
+}
+
+func (f *mapFieldConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	if skipMerge(f.mergeMode, f.converter.sType, sPtr) {
+		return false
+	}
+	cs.pushField(f.dFieldName)
+	defer cs.pop()
+	return f.converter.convert(dPtr, sPtr, cs)
+}
+
+func newMapFieldConverter(df structItem, keyType, valueType reflect.Type, option *StructOption, custom []CustomConverterV2) *mapFieldConverter {
+	// 非any的接口类型无法确定实现类型，跳过
+	if t, _ := referDeep(df.typ); t.Kind() == reflect.Interface && t != ptr.AnyType {
+		return nil
+	}
+	option, err := tagOption(option, df.format, df.unit, df.tz)
+	var ec *elemConverter
+	var ok bool
+	switch {
+	case err != nil:
+		ec, ok = newInvalidElemConverter(df.typ, valueType, err), true
+	case df.itemType == typeOneof:
+		ec, ok = newOneofElemConverter(df, structItem{itemType: typeField, typ: valueType}, option, custom)
+	default:
+		ec, ok = newElemConverter(df.typ, valueType, option, custom...)
 	}
+	if !ok {
+		return nil
+	}
+	return &mapFieldConverter{
+		converter:     ec,
+		dStructType:   df.structType,
+		dAnonymousPtr: df.anonymousPtr,
+		dOffset:       df.offset,
+		dName:         df.name,
+		dFieldName:    df.filedName,
+		sKey:          reflect.ValueOf(df.name).Convert(keyType),
+		mergeMode:     option.MergeMode,
+		dRepeated:     isRepeatedType(ec.dDereferType),
+	}
+}
+
+// skipMerge 合并模式下源值为零值或nil时跳过，不覆盖目标
+func skipMerge(mode MergeMode, typ reflect.Type, sPtr unsafe.Pointer) bool {
+	switch mode {
+	case MergeModeSkipZero:
+		return reflect.NewAt(typ, sPtr).Elem().IsZero()
+	case MergeModeSkipNil:
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+			return reflect.NewAt(typ, sPtr).Elem().IsNil()
+		}
+	}
+	return false
+}
+
+// tagOption 字段tag中的format、unit、tz优先于option，无法识别的unit和tz返回ErrInvalidTag
+func tagOption(option *StructOption, format, unit, tz string) (*StructOption, error) {
+	timeUnit, unitOk := timeUnits[unit]
+	if len(unit) > 0 && !unitOk {
+		return option, fmt.Errorf("%w: unit:%q", ErrInvalidTag, unit)
+	}
+	var loc *time.Location
+	if len(tz) > 0 {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return option, fmt.Errorf("%w: tz:%q: %v", ErrInvalidTag, tz, err)
+		}
+	}
+	if len(format) == 0 && !unitOk && loc == nil {
+		return option, nil
+	}
+	option = gvalue.Safe(option.Clone())
+	if len(format) > 0 {
+		option.TimeFormat = format
+	}
+	if unitOk {
+		option.TimeUnit = timeUnit
+	}
+	if loc != nil {
+		option.TimeLocation = loc
+	}
+	return option, nil
+}
+
+// invalidConverter 构建时发现的无效配置（如无法识别的tag），每次转换都返回该错误且不写入目标
+type invalidConverter struct {
+	dstTyp reflect.Type
+	srcTyp reflect.Type
+	err    error
+}
+
+func (c *invalidConverter) convert(_, _ unsafe.Pointer, cs *convState) bool {
+	return cs.failHook(c.dstTyp, c.srcTyp, c.err)
+}
+
+func newInvalidElemConverter(dType, sType reflect.Type, err error) *elemConverter {
+	ec := &elemConverter{dType: dType, sType: sType}
+	ec.dDereferType, ec.dReferDeep = referDeep(dType)
+	ec.sDereferType, ec.sReferDeep = referDeep(sType)
+	ec.sEmptyDereferValPtr = newValuePtr(ec.sDereferType)
+	ec.converter = &invalidConverter{dstTyp: ec.dDereferType, srcTyp: ec.sDereferType, err: err}
+	return ec
 }
 
 func getFieldName(f reflect.StructField, opt *StructOption) string {
@@ -391,6 +662,7 @@ const (
 	typeField = iota + 1
 	typeFieldMethod
 	typeMethod
+	typeOneof // proto消息oneof的一个分支
 )
 
 type funcOutType int64
@@ -407,19 +679,25 @@ type structItem struct {
 	name         string
 	filedName    string
 	format       string
+	unit         string
+	tz           string
 	typ          reflect.Type
 	structType   reflect.Type
 	anonymousPtr []bool
 	offset       []uintptr
+	oneof        string       // 所属的oneof名
+	oneofIface   reflect.Type // oneof接口字段类型
+	oneofWrapper reflect.Type // 分支的包装类型，如*Msg_Card
 }
 
 func newStructItem(structType reflect.Type) *structItem {
 	return &structItem{structType: structType}
 }
 
-func (s *structItem) setField(field reflect.StructField, anonymousPtr []bool, offset []uintptr) *structItem {
+func (s *structItem) setField(field reflect.StructField, fieldName string, anonymousPtr []bool, offset []uintptr) *structItem {
 	s.itemType = typeField
 	s.name = field.Name
+	s.filedName = fieldName
 	s.typ = field.Type
 	s.anonymousPtr = anonymousPtr
 	s.offset = offset
@@ -451,7 +729,8 @@ func extractFields(t reflect.Type, opt *StructOption, fieldMap map[string]*struc
 	if opt == nil {
 		opt = defaultStructOption()
 	}
-	isProto := isProtoMessage(t)
+	// 方法挂在指针接收者上
+	isProto := isProtoMessage(reflect.PointerTo(t))
 	if fieldMap == nil {
 		fieldMap = make(map[string]*structItem)
 	}
@@ -461,6 +740,15 @@ func extractFields(t reflect.Type, opt *StructOption, fieldMap map[string]*struc
 		if isProto && protoPrivateField.Contains(f.Name) {
 			continue
 		}
+		if isProto && len(f.Tag.Get("protobuf_oneof")) > 0 {
+			for _, c := range oneofCases(t, f, opt, anonymousPtr, append(gslice.Clone(offset), f.Offset)) {
+				if _, ok := fieldMap[c.name]; !ok {
+					fieldMap[c.name] = c
+					fieldSlice = append(fieldSlice, c)
+				}
+			}
+			continue
+		}
 		sf := newStructItem(t)
 		fieldName := f.Name
 		if !opt.IgnoreTag {
@@ -468,6 +756,8 @@ func extractFields(t reflect.Type, opt *StructOption, fieldMap map[string]*struc
 				continue
 			}
 			sf.format = f.Tag.Get("format")
+			sf.unit = f.Tag.Get("unit")
+			sf.tz = f.Tag.Get("tz")
 		}
 		if !opt.IgnoreFunc && f.Type.Kind() == reflect.Func && f.Type.NumIn() == 0 {
 			if outSize := f.Type.NumOut(); outSize == 1 {
@@ -481,7 +771,7 @@ func extractFields(t reflect.Type, opt *StructOption, fieldMap map[string]*struc
 				}
 			}
 		} else {
-			sf.setField(f, anonymousPtr, append(gslice.Clone(offset), f.Offset))
+			sf.setField(f, fieldName, anonymousPtr, append(gslice.Clone(offset), f.Offset))
 		}
 		if !opt.IgnorePrivateFields || unicode.IsUpper(rune(fieldName[0])) {
 			if _, ok := fieldMap[f.Name]; ok {
@@ -509,13 +799,22 @@ func extractFieldsOnMap(t reflect.Type, opt *StructOption, fieldMap map[string]*
 	if opt == nil {
 		opt = defaultStructOption()
 	}
-	proto := isProtoMessage(t)
+	proto := isProtoMessage(reflect.PointerTo(t))
 	anonymous := make([]*structItem, 0, t.NumField())
 	for i, n := 0, t.NumField(); i < n; i++ {
 		f := t.Field(i)
 		if proto && protoPrivateField.Contains(f.Name) {
 			continue
 		}
+		if proto && len(f.Tag.Get("protobuf_oneof")) > 0 {
+			for _, c := range oneofCases(t, f, opt, anonymousPtr, append(gslice.Clone(offset), f.Offset)) {
+				if _, ok := fieldMap[c.name]; !ok {
+					fieldMap[c.name] = c
+					fieldSlice = append(fieldSlice, c)
+				}
+			}
+			continue
+		}
 		if f.Type.Kind() == reflect.Func {
 			continue
 		}
@@ -526,8 +825,10 @@ func extractFieldsOnMap(t reflect.Type, opt *StructOption, fieldMap map[string]*
 				continue
 			}
 			sf.format = f.Tag.Get("format")
+			sf.unit = f.Tag.Get("unit")
+			sf.tz = f.Tag.Get("tz")
 		}
-		sf.setField(f, anonymousPtr, append(gslice.Clone(offset), f.Offset))
+		sf.setField(f, fieldName, anonymousPtr, append(gslice.Clone(offset), f.Offset))
 		if opt.IncludePrivateFields || unicode.IsUpper(rune(fieldName[0])) {
 			if _, ok := fieldMap[f.Name]; ok {
 				continue
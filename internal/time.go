@@ -6,8 +6,12 @@
 package internal
 
 import (
+	"fmt"
+	"github.com/smgrushb/conv/internal/generics/collection/set"
 	"github.com/smgrushb/conv/internal/generics/gvalue"
+	"math"
 	"reflect"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -17,8 +21,37 @@ var (
 	TimeFormat   = "2006-01-02 15:04:05"
 	MinUnix      *int64
 	MinUnixScene = DefaultMinUnixScene
+	TimeLocation *time.Location
 )
 
+// timeUnits 字段tag中unit支持的时间戳单位
+var timeUnits = map[string]time.Duration{
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"us": time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+const (
+	timeFormatSep  = "|"
+	timeFormatAuto = "auto"
+)
+
+// autoTimeLayouts format为auto时依次尝试的常见ISO-8601格式，秒的小数部分解析时自动兼容
+var autoTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"20060102T150405Z0700",
+	"20060102T150405",
+	"20060102",
+}
+
 func init() {
 	TimeWrappers = append(TimeWrappers, &TimeWrapper[time.Time]{})
 }
@@ -50,49 +83,206 @@ func (t *TimeWrapper[T]) GetFormat() (string, bool) {
 type asTime func(unsafe.Pointer) *time.Time
 
 type timeConverter struct {
+	*convertType
 	format  string
+	layouts []string
+	loc     *time.Location // 为nil时解析使用time.Local，输出保留原时区
+	unit    time.Duration
 	minUnix *int64
 	as      asTime
-	cvtOp   func(string, *int64, asTime, unsafe.Pointer, unsafe.Pointer) bool
+	cvtOp   func(*timeConverter, unsafe.Pointer, unsafe.Pointer, *convState) bool
 }
 
-func (t *timeConverter) convert(dPtr, sPtr unsafe.Pointer) bool {
-	return t.cvtOp(t.format, t.minUnix, t.as, dPtr, sPtr)
+func (t *timeConverter) convert(dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	return t.cvtOp(t, dPtr, sPtr, cs)
 }
 
-func cvtTimeString(format string, minUnix *int64, as asTime, dPtr, sPtr unsafe.Pointer) bool {
-	t := as(sPtr)
-	if minUnix != nil && t.Unix() < *minUnix {
+func cvtTimeString(tc *timeConverter, dPtr, sPtr unsafe.Pointer, _ *convState) bool {
+	t := tc.as(sPtr)
+	if tc.minUnix != nil && t.Unix() < *tc.minUnix {
 		return false
 	}
-	*(*string)(dPtr) = t.Format(format)
+	if tc.loc != nil {
+		*(*string)(dPtr) = t.In(tc.loc).Format(tc.format)
+	} else {
+		*(*string)(dPtr) = t.Format(tc.format)
+	}
 	return true
 }
 
 var (
-	localZeroUnix = time.Date(1970, 1, 1, 0, 0, 0, 0, time.Local)
-	localZeroTime = time.Date(1, 1, 1, 0, 0, 0, 0, time.Local)
-	utcZeroUnix   = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
-	utcZeroTime   = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	utcZeroUnix = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	utcZeroTime = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
 )
 
-func cvtStringTime(format string, minUnix *int64, as asTime, dPtr, sPtr unsafe.Pointer) bool {
-	t, err := time.ParseInLocation(format, *(*string)(sPtr), time.Local)
+func cvtStringTime(tc *timeConverter, dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	loc := tc.loc
+	if loc == nil {
+		loc = time.Local
+	}
+	t, err := parseTime(tc.layouts, *(*string)(sPtr), loc)
 	if err != nil {
-		return false
+		return cs.fail(tc.dstTyp, tc.srcTyp, err)
 	}
-	if minUnix != nil && t.Unix() < *minUnix {
+	if tc.minUnix != nil && t.Unix() < *tc.minUnix {
 		return false
 	}
-	if t.Equal(localZeroUnix) {
+	if t.Equal(time.Date(1970, 1, 1, 0, 0, 0, 0, loc)) {
 		t = utcZeroUnix
-	} else if t.Equal(localZeroTime) {
+	} else if t.Equal(time.Date(1, 1, 1, 0, 0, 0, 0, loc)) {
 		t = utcZeroTime
 	}
-	*as(dPtr) = t
+	*tc.as(dPtr) = t
 	return true
 }
 
+// parseTime 按顺序尝试layouts，全部失败时只有一个layout则返回原始错误
+func parseTime(layouts []string, value string, loc *time.Location) (time.Time, error) {
+	var firstErr error
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, value, loc)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if len(layouts) == 1 {
+		return time.Time{}, firstErr
+	}
+	return time.Time{}, fmt.Errorf("parsing time %q: no layout matched in %q", value, layouts)
+}
+
+// splitTimeFormat 按|拆分多个layout，auto展开为常见的ISO-8601格式
+func splitTimeFormat(format string) []string {
+	if !strings.Contains(format, timeFormatSep) && format != timeFormatAuto {
+		return []string{format}
+	}
+	var layouts []string
+	seen := set.New[string]()
+	for _, v := range strings.Split(format, timeFormatSep) {
+		items := []string{v}
+		if v == timeFormatAuto {
+			items = autoTimeLayouts
+		}
+		for _, layout := range items {
+			if len(layout) > 0 && !seen.Contains(layout) {
+				seen.Add(layout)
+				layouts = append(layouts, layout)
+			}
+		}
+	}
+	if len(layouts) == 0 {
+		return []string{format}
+	}
+	return layouts
+}
+
+// cvtTimeNumber 零值时间转为0，超出目标类型范围时报错且不写入
+func cvtTimeNumber(tc *timeConverter, dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	t := tc.as(sPtr)
+	if tc.minUnix != nil && t.Unix() < *tc.minUnix {
+		return false
+	}
+	dv := reflect.NewAt(tc.dstTyp, dPtr).Elem()
+	if t.IsZero() {
+		dv.Set(reflect.Zero(tc.dstTyp))
+		return true
+	}
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	switch dv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		dv.SetFloat(float64(sec)*(float64(time.Second)/float64(tc.unit)) + float64(nsec)/float64(tc.unit))
+		return true
+	}
+	var v int64
+	if tc.unit < time.Second {
+		per := int64(time.Second / tc.unit)
+		if sec > (math.MaxInt64-nsec/int64(tc.unit))/per || sec < math.MinInt64/per {
+			return cs.fail(tc.dstTyp, tc.srcTyp, ErrOutOfRange)
+		}
+		v = sec*per + nsec/int64(tc.unit)
+	} else {
+		v = sec / int64(tc.unit/time.Second)
+	}
+	if dv.CanInt() {
+		if dv.OverflowInt(v) {
+			return cs.fail(tc.dstTyp, tc.srcTyp, ErrOutOfRange)
+		}
+		dv.SetInt(v)
+	} else {
+		if v < 0 || dv.OverflowUint(uint64(v)) {
+			return cs.fail(tc.dstTyp, tc.srcTyp, ErrOutOfRange)
+		}
+		dv.SetUint(uint64(v))
+	}
+	return true
+}
+
+// cvtNumberTime 0转为零值时间，超出time.Time可表示范围时报错且不写入
+func cvtNumberTime(tc *timeConverter, dPtr, sPtr unsafe.Pointer, cs *convState) bool {
+	sv := reflect.NewAt(tc.srcTyp, sPtr).Elem()
+	var t time.Time
+	switch {
+	case sv.IsZero():
+	case sv.CanFloat():
+		f := sv.Float() * float64(tc.unit) / float64(time.Second)
+		if math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+			return cs.fail(tc.dstTyp, tc.srcTyp, ErrOutOfRange)
+		}
+		sec, frac := math.Modf(f)
+		t = time.Unix(int64(sec), int64(frac*float64(time.Second)))
+	default:
+		var v int64
+		if sv.CanInt() {
+			v = sv.Int()
+		} else if u := sv.Uint(); u > math.MaxInt64 {
+			return cs.fail(tc.dstTyp, tc.srcTyp, ErrOutOfRange)
+		} else {
+			v = int64(u)
+		}
+		if tc.unit < time.Second {
+			per := int64(time.Second / tc.unit)
+			t = time.Unix(v/per, v%per*int64(tc.unit))
+		} else {
+			mul := int64(tc.unit / time.Second)
+			if v > math.MaxInt64/mul || v < math.MinInt64/mul {
+				return cs.fail(tc.dstTyp, tc.srcTyp, ErrOutOfRange)
+			}
+			t = time.Unix(v*mul, 0)
+		}
+	}
+	if tc.minUnix != nil && t.Unix() < *tc.minUnix {
+		return false
+	}
+	if tc.loc != nil && !t.IsZero() {
+		t = t.In(tc.loc)
+	}
+	*tc.as(dPtr) = t
+	return true
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Uint64) || k == reflect.Float32 || k == reflect.Float64
+}
+
+// getTimeUnit 只支持能整除或被整除秒的单位，其余按秒处理
+func getTimeUnit(option *StructOption) time.Duration {
+	if option != nil && option.TimeUnit > 0 && (time.Second%option.TimeUnit == 0 || option.TimeUnit%time.Second == 0) {
+		return option.TimeUnit
+	}
+	return time.Second
+}
+
+// GetTimeLocation 时区的优先级是option > global，均未指定时返回nil
+func GetTimeLocation(option *StructOption) *time.Location {
+	if option != nil && option.TimeLocation != nil {
+		return option.TimeLocation
+	}
+	return TimeLocation
+}
+
 func getTimeFormat(tw timeWrapper, option *StructOption) string {
 	if option != nil && len(option.TimeFormat) > 0 {
 		return option.TimeFormat
@@ -121,17 +311,31 @@ func getMinUnix(option *StructOption, scene MinUnixSceneType) *int64 {
 }
 
 func newTimeConverter(typ *convertType) converter {
-	// todo: time.Time和时间戳互转
+	if dk, sk := typ.dstTyp.Kind(), typ.srcTyp.Kind(); isNumberKind(dk) {
+		for _, v := range TimeWrappers {
+			if v.Is(typ.srcTyp) {
+				return &timeConverter{convertType: typ, unit: getTimeUnit(typ.option), minUnix: getMinUnix(typ.option, MinUnixTimeNumber), as: v.As, cvtOp: cvtTimeNumber}
+			}
+		}
+	} else if isNumberKind(sk) {
+		for _, v := range TimeWrappers {
+			if v.Is(typ.dstTyp) {
+				return &timeConverter{convertType: typ, loc: GetTimeLocation(typ.option), unit: getTimeUnit(typ.option), minUnix: getMinUnix(typ.option, MinUnixNumberTime), as: v.As, cvtOp: cvtNumberTime}
+			}
+		}
+	}
 	if typ.dstTyp.Kind() == reflect.String {
 		for _, v := range TimeWrappers {
 			if v.Is(typ.srcTyp) {
-				return &timeConverter{format: getTimeFormat(v, typ.option), minUnix: getMinUnix(typ.option, MinUnixTimeString), as: v.As, cvtOp: cvtTimeString}
+				// 多个layout时使用第一个格式化
+				format := splitTimeFormat(getTimeFormat(v, typ.option))[0]
+				return &timeConverter{convertType: typ, format: format, loc: GetTimeLocation(typ.option), minUnix: getMinUnix(typ.option, MinUnixTimeString), as: v.As, cvtOp: cvtTimeString}
 			}
 		}
 	} else if typ.srcTyp.Kind() == reflect.String {
 		for _, v := range TimeWrappers {
 			if v.Is(typ.dstTyp) {
-				return &timeConverter{format: getTimeFormat(v, typ.option), minUnix: getMinUnix(typ.option, MinUnixStringTime), as: v.As, cvtOp: cvtStringTime}
+				return &timeConverter{convertType: typ, layouts: splitTimeFormat(getTimeFormat(v, typ.option)), loc: GetTimeLocation(typ.option), minUnix: getMinUnix(typ.option, MinUnixStringTime), as: v.As, cvtOp: cvtStringTime}
 			}
 		}
 	}
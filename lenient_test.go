@@ -0,0 +1,80 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"github.com/smgrushb/conv/option"
+	"testing"
+)
+
+func lenient[T any](s string) (any, error) {
+	return Convert[T](s, option.Lenient(), option.Strict())
+}
+
+// 宽松解析：空白、进制前缀、分隔符、小数部分为0的浮点数，前导0按十进制
+func TestLenientParse(t *testing.T) {
+	cases := []struct {
+		name string
+		conv func(string) (any, error)
+		src  string
+		want any
+		err  bool
+	}{
+		{"spaces", lenient[int], " 12 ", 12, false},
+		{"spacesUint", lenient[uint], "\t7\n", uint(7), false},
+		{"spacesBool", lenient[bool], " true ", true, false},
+		{"hex", lenient[int], "0x1F", 31, false},
+		{"negativeBinary", lenient[int8], "-0b11", int8(-3), false},
+		{"octal", lenient[uint], "0o17", uint(15), false},
+		{"upperHex", lenient[uint16], "0XFF", uint16(255), false},
+		{"hexFloat", lenient[float64], "0x10", float64(16), false},
+		{"thousands", lenient[int], "1,234", 1234, false},
+		{"underscore", lenient[uint], "1_000", uint(1000), false},
+		{"thousandsFloat", lenient[float64], "1,234.50", 1234.5, false},
+		{"underscoreFloat32", lenient[float32], "2_5.5", float32(25.5), false},
+		{"leadingZero", lenient[int], "007", 7, false},
+		{"leadingZeroUint", lenient[uint], "010", uint(10), false},
+		{"wholeFloat", lenient[int], "1.0", 1, false},
+		{"negativeWholeFloat", lenient[int8], "-2.00", int8(-2), false},
+		{"exponent", lenient[uint], "3e2", uint(300), false},
+		{"fraction", lenient[int], "1.5", nil, true},
+		{"negativeUint", lenient[uint], "-1.0", nil, true},
+		{"overflow", lenient[int8], "128", nil, true},
+		{"floatOverflow", lenient[int8], "1e3", nil, true},
+		{"uintOverflow", lenient[uint8], "256.0", nil, true},
+		{"garbageBool", lenient[bool], "yes", nil, true},
+		{"empty", lenient[int], "  ", nil, true},
+	}
+	for _, c := range cases {
+		got, err := c.conv(c.src)
+		if (err != nil) != c.err {
+			t.Fatalf("%s: want error %v, got %v", c.name, c.err, err)
+		}
+		if !c.err && got != c.want {
+			t.Fatalf("%s: want %v(%T), got %v(%T)", c.name, c.want, c.want, got, got)
+		}
+	}
+}
+
+// 非严格模式下解析失败不报错，溢出时取边界值
+func TestLenientNonStrict(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		want int8
+	}{
+		{"ok", " 0x10 ", 16},
+		{"overflow", "1,000", 127},
+		{"underflow", "-1e3", -128},
+		{"garbage", "abc", 0},
+	}
+	for _, c := range cases {
+		got, err := Convert[int8](c.src, option.Lenient())
+		if err != nil || got != c.want {
+			t.Fatalf("%s: want %d, got %d, %v", c.name, c.want, got, err)
+		}
+	}
+}
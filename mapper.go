@@ -0,0 +1,90 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"errors"
+	"fmt"
+	"github.com/smgrushb/conv/internal"
+	"github.com/smgrushb/conv/internal/generics/gvalue"
+	"github.com/smgrushb/conv/option"
+	"reflect"
+	"unsafe"
+)
+
+// Mapper 预先构建的类型化转换器，转换时无需反射校验和缓存查找，可并发使用
+type Mapper[To, From any] struct {
+	c *internal.ElemConverter
+}
+
+// NewMapper 按配置构建Mapper，类型不支持转换时返回错误
+func NewMapper[To, From any](opts ...option.Option) (*Mapper[To, From], error) {
+	dstTyp, srcTyp := internal.ReflectType[To](), internal.ReflectType[From]()
+	// 接口类型且不是any
+	if dstTyp.Kind() == reflect.Interface && !internal.IsAnyType[To]() {
+		return nil, fmt.Errorf("bad destination type:%s", gvalue.ReflectPathType[To]())
+	}
+	if srcTyp.Kind() == reflect.Interface && !internal.IsAnyType[From]() {
+		return nil, fmt.Errorf("bad source type:%s", gvalue.ReflectPathType[From]())
+	}
+	c := internal.NewElemConverter(dstTyp, srcTyp, internal.GetOption(0, opts...))
+	if c == nil {
+		return nil, fmt.Errorf("can't convert source type %s to destination type %s", srcTyp, dstTyp)
+	}
+	return &Mapper[To, From]{c: c}, nil
+}
+
+// MustNewMapper 同NewMapper，无法转换时panic，适合用于初始化全局变量
+func MustNewMapper[To, From any](opts ...option.Option) *Mapper[To, From] {
+	m, err := NewMapper[To, From](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Map 转换为新值，出错时返回零值
+func (m *Mapper[To, From]) Map(from From) (to To, err error) {
+	if err = m.c.Convert(unsafe.Pointer(&to), unsafe.Pointer(&from)); err != nil {
+		return gvalue.Zero[To](), err
+	}
+	return
+}
+
+// MapInto 转换到已有的值上，出错时保留已转换成功的字段
+func (m *Mapper[To, From]) MapInto(to *To, from From) error {
+	if to == nil {
+		return errors.New("[conv]destination should be a pointer")
+	}
+	return m.c.Convert(unsafe.Pointer(to), unsafe.Pointer(&from))
+}
+
+// MapSlice 逐个转换切片元素，nil切片返回nil
+func (m *Mapper[To, From]) MapSlice(from []From) ([]To, error) {
+	if from == nil {
+		return nil, nil
+	}
+	res := make([]To, len(from))
+	if len(from) == 0 {
+		return res, nil
+	}
+	if err := m.c.ConvertSlice(unsafe.Pointer(&res[0]), unsafe.Pointer(&from[0]), len(from)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// MapPtr 转换指针指向的值，nil返回nil
+func (m *Mapper[To, From]) MapPtr(from *From) (*To, error) {
+	if from == nil {
+		return nil, nil
+	}
+	to := new(To)
+	if err := m.c.Convert(unsafe.Pointer(to), unsafe.Pointer(from)); err != nil {
+		return nil, err
+	}
+	return to, nil
+}
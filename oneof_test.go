@@ -0,0 +1,79 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"testing"
+)
+
+type flatValue struct {
+	NumberValue float64
+	StringValue string
+	BoolValue   *bool
+}
+
+type unionKind struct {
+	NumberValue *float64
+	StringValue *string
+}
+
+type unionValue struct {
+	Kind unionKind
+}
+
+// 平铺的结构体按非零值的字段写入分支，多个非零时后面的分支生效
+func TestOneofFromFlat(t *testing.T) {
+	cases := []struct {
+		name string
+		src  any
+		want *structpb.Value
+	}{
+		{"string", flatValue{StringValue: "x"}, structpb.NewStringValue("x")},
+		{"number", flatValue{NumberValue: 1.5}, structpb.NewNumberValue(1.5)},
+		{"falsePointer", flatValue{BoolValue: proto.Bool(false)}, structpb.NewBoolValue(false)},
+		{"lastWins", flatValue{NumberValue: 1.5, StringValue: "x"}, structpb.NewStringValue("x")},
+		{"none", flatValue{}, &structpb.Value{}},
+		{"union", unionValue{Kind: unionKind{StringValue: proto.String("u")}}, structpb.NewStringValue("u")},
+		{"unionEmpty", unionValue{}, &structpb.Value{}},
+		{"mapKey", map[string]any{"number_value": 2}, structpb.NewNumberValue(2)},
+	}
+	for _, c := range cases {
+		got, err := Convert[*structpb.Value](c.src)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !proto.Equal(got, c.want) {
+			t.Fatalf("%s: want %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+// 只有当前分支对应的字段被写入
+func TestOneofToStruct(t *testing.T) {
+	flat := flatValue{NumberValue: 9, StringValue: "old"}
+	if err := ConvertTo(structpb.NewStringValue("x"), &flat); err != nil {
+		t.Fatal(err)
+	}
+	if flat.StringValue != "x" || flat.NumberValue != 9 || flat.BoolValue != nil {
+		t.Fatalf("flat: got %+v", flat)
+	}
+	union, err := Convert[unionValue](structpb.NewNumberValue(2.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if union.Kind.NumberValue == nil || *union.Kind.NumberValue != 2.5 || union.Kind.StringValue != nil {
+		t.Fatalf("union: got %+v", union.Kind)
+	}
+	m, err := Convert[map[string]any](structpb.NewBoolValue(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 1 || m["bool_value"] != true {
+		t.Fatalf("map: got %v", m)
+	}
+}
@@ -14,6 +14,10 @@ import (
 	"github.com/smgrushb/conv/internal"
 	"github.com/smgrushb/conv/internal/generics/gptr"
 	"github.com/smgrushb/conv/internal/generics/gslice"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"strings"
 	"time"
 )
 
@@ -57,15 +61,38 @@ func AliasMap(m map[string]string) Option {
 	}
 }
 
-// TimeFormat 指定time.Time转string的format格式
+// TimeFormat 指定time.Time与string互转的format格式，可用|分隔多个格式（auto表示常见的ISO-8601格式），
+// string转time.Time时依次尝试，time.Time转string时使用第一个
 func TimeFormat(format string) Option {
 	return func(o *internal.StructOption) {
 		o.TimeFormat = format
 	}
 }
 
+// TimeFormats 指定多个time.Time与string互转的format格式，同TimeFormat(strings.Join(formats, "|"))
+func TimeFormats(formats ...string) Option {
+	return func(o *internal.StructOption) {
+		o.TimeFormat = strings.Join(formats, "|")
+	}
+}
+
+// TimeLocation 指定时间转换使用的时区：string转time.Time时按此时区解析，time.Time转string、
+// 时间戳转time.Time、protobuf Timestamp转time.Time时转换到此时区，可通过字段tag（tz:"Asia/Shanghai"）单独指定
+func TimeLocation(loc *time.Location) Option {
+	return func(o *internal.StructOption) {
+		o.TimeLocation = loc
+	}
+}
+
+// TimeUnit 指定time.Time与整数/浮点数互转时时间戳的单位，默认秒，可通过字段tag（unit:"s|ms|us|ns"）单独指定
+func TimeUnit(unit time.Duration) Option {
+	return func(o *internal.StructOption) {
+		o.TimeUnit = unit
+	}
+}
+
 // MinUnix 指定time.Time(包含time.Time各别名类型)转换到非time.Time(包含time.Time各别名类型)时的最低有效时间，低于此时间则不转换
-// 目前场景：time/string互转(默认生效)，time转any(默认不生效)
+// 目前场景：time/string互转(默认生效)，time/数值互转(默认生效)，time转any(默认不生效)
 // 可以传入scene来自订生效规则
 // 注意：time.Time同类型互转(包含time.Time各别名类型)时将直接浅拷贝，此配置被绕过，不生效
 // 注意：别名类型仅检查被RegisterTimeWrapper方法注册的
@@ -81,7 +108,7 @@ func MinUnix(unix int64, scene ...internal.MinUnixSceneType) Option {
 }
 
 // MinUnixBy 指定time.Time(包含time.Time各别名类型)转换到非time.Time(包含time.Time各别名类型)时的最低有效时间，低于此时间则不转换
-// 目前场景：time/string互转(默认生效)，time转any(默认不生效)
+// 目前场景：time/string互转(默认生效)，time/数值互转(默认生效)，time转any(默认不生效)
 // 可以传入scene来自订生效规则
 // 注意：time.Time同类型互转(包含time.Time各别名类型)时将直接浅拷贝，此配置被绕过，不生效
 // 注意：别名类型仅检查被RegisterTimeWrapper方法注册的
@@ -110,14 +137,14 @@ func PriorityTagName(name string) Option {
 	}
 }
 
-// IgnorePrivateFields 屏蔽私有字段(结构体转map外场景生效)
+// IgnorePrivateFields 屏蔽私有字段(结构体与map互转外场景生效)
 func IgnorePrivateFields() Option {
 	return func(o *internal.StructOption) {
 		o.IgnorePrivateFields = true
 	}
 }
 
-// IncludePrivateFields 包含私有字段(仅结构体转map场景生效)
+// IncludePrivateFields 包含私有字段(仅结构体与map互转场景生效)
 func IncludePrivateFields() Option {
 	return func(o *internal.StructOption) {
 		o.IncludePrivateFields = true
@@ -173,6 +200,46 @@ func StrBytesZeroCopy(zeroCopy ...bool) Option {
 	}
 }
 
+// ReportErrors 收集转换过程中的错误（如字符串解析失败、方法返回error、序列化失败），
+// 以conv.ConvertErrors返回，每个错误带有目标字段路径、源类型和目标类型，默认静默忽略
+func ReportErrors() Option {
+	return func(o *internal.StructOption) {
+		o.ReportErrors = true
+	}
+}
+
+// Strict 严格模式，string转数值/bool解析失败、数值间转换溢出或丢失精度（如int64转int8、float64转int、uint转int）时转换失败，
+// 不写入目标并返回错误（同时开启ReportErrors），浮点数之间仅在溢出时失败
+func Strict() Option {
+	return func(o *internal.StructOption) {
+		o.Strict = true
+		o.ReportErrors = true
+	}
+}
+
+// Lenient 宽松模式，string转数值/bool时去除首尾空白，支持0x/0o/0b前缀、_和千分位分隔符（如1,234.50），
+// 转整数时接受小数部分为0的浮点数（如1.0），可与Strict同时使用
+func Lenient() Option {
+	return func(o *internal.StructOption) {
+		o.Lenient = true
+	}
+}
+
+// DeepCopy 深拷贝模式，同类型转换时重新分配切片、数组、map、指针和string，结果与源数据不共享内存，
+// 同时关闭string与[]byte互转的零拷贝；默认同类型直接按位复制（浅拷贝）
+func DeepCopy() Option {
+	return func(o *internal.StructOption) {
+		o.DeepCopy = true
+		o.StrBytesZeroCopy = false
+	}
+}
+
+// Compile 预先解析配置并计算缓存key，返回的配置可复用，单独使用时每次转换无需重新解析配置，
+// 注意：全局配置（如SetStructTageName、SetTimeFormat）在Compile时生效；解析结果常驻内存，应在初始化时调用一次并复用
+func Compile(opts ...Option) Option {
+	return internal.CompileOption(opts...)
+}
+
 // CustomConverter 自定义转换器
 func CustomConverter(custom ...internal.CustomConverter) Option {
 	return func(o *internal.StructOption) {
@@ -180,6 +247,48 @@ func CustomConverter(custom ...internal.CustomConverter) Option {
 	}
 }
 
+// FieldConverter 注册字段级自定义转换器，只作用于指定的目标字段（字段名规则与Banned一致，支持a.b形式的嵌套字段），
+// 优先于CustomConverter/CustomConverterV2等按类型匹配的转换器，类型不匹配时回退到按类型匹配
+func FieldConverter(field string, custom ...internal.CustomConverterV2) Option {
+	return func(o *internal.StructOption) {
+		o.FieldConv[field] = append(o.FieldConv[field], custom...)
+	}
+}
+
+// Func 由func(From) (To, error)生成自定义转换器，无需实现CustomConverterV2，
+// To和From可以是指针（按去掉指针后的类型匹配），内置类型（如int64）同时匹配以其为底层类型的自定义类型，
+// 返回nil指针时不写入目标，返回的错误通过Convert返回（不受ReportErrors控制）。
+// 注意：转换器按函数实例区分，应复用同一个函数，每次转换都新建闭包会导致转换器重复构建
+func Func[To, From any](fn func(From) (To, error)) Option {
+	return CustomConverterV2(FuncConverter(fn))
+}
+
+// FuncInto 同Func，由func(*To, From) error生成自定义转换器，直接写入目标
+func FuncInto[To, From any](fn func(*To, From) error) Option {
+	return CustomConverterV2(FuncIntoConverter(fn))
+}
+
+// FuncConverter 同Func，返回转换器本身，用于FieldConverter、PriorityConverter等，
+// 如FieldConverter("created_at", FuncConverter(parseDate))
+func FuncConverter[To, From any](fn func(From) (To, error)) internal.CustomConverterV2 {
+	return internal.NewFuncConverter(fn)
+}
+
+// FuncIntoConverter 同FuncInto，返回转换器本身
+func FuncIntoConverter[To, From any](fn func(*To, From) error) internal.CustomConverterV2 {
+	return internal.NewFuncIntoConverter(fn)
+}
+
+// Hook 注册From转To的结构体转换钩子，before在字段转换前调用，after在字段转换完成后调用，不需要的传nil，
+// 钩子返回的错误会中止该结构体的转换并通过Convert返回（不受ReportErrors控制）。
+// 注意：钩子按函数实例区分，应复用同一个函数（如包级函数），每次转换都新建闭包会导致转换器重复构建
+func Hook[To, From any](before, after func(dst *To, src *From) error) Option {
+	hook := internal.NewHook(before, after)
+	return func(o *internal.StructOption) {
+		o.Hooks = append(o.Hooks, hook)
+	}
+}
+
 // CustomConverterV2 自定义转换器
 func CustomConverterV2(custom ...internal.CustomConverterV2) Option {
 	return func(o *internal.StructOption) {
@@ -187,6 +296,17 @@ func CustomConverterV2(custom ...internal.CustomConverterV2) Option {
 	}
 }
 
+// PriorityConverter 以指定优先级注册自定义转换器，多个转换器匹配同一类型对时优先级高的生效，
+// 同优先级时CustomConverterV2（含Func、FuncInto、ConvProto）优先于CustomConverter，再按注册顺序，
+// 未指定优先级的转换器为0（内置Proto转换器也是0且总是在用户转换器之后注册）
+func PriorityConverter(priority int, custom ...internal.CustomConverterV2) Option {
+	return func(o *internal.StructOption) {
+		for _, v := range custom {
+			o.CustomConvV2 = append(o.CustomConvV2, internal.WithPriority(v, priority))
+		}
+	}
+}
+
 // ConvProto 自定义Proto转换器
 // 自动携带所有基础转换器
 func ConvProto(custom ...internal.CustomConverterV2) Option {
@@ -216,3 +336,132 @@ func NilValuePolicy(policy internal.NilValuePolicy) Option {
 		o.NilValuePolicy = policy
 	}
 }
+
+// ArrayLengthPolicy 配置转换到数组时源长度与目标数组长度不一致的处理策略。
+// 切片/数组转切片时目标长度始终与源一致，不受此配置影响。
+//
+// 支持的策略:
+// - ArrayLengthPolicyZeroFill: 超出部分截断，不足部分目标元素置零值（默认）。
+// - ArrayLengthPolicyKeep: 超出部分截断，不足部分保留目标元素原值。
+// - ArrayLengthPolicyExact: 长度不一致时不转换。
+func ArrayLengthPolicy(policy internal.ArrayLengthPolicy) Option {
+	return func(o *internal.StructOption) {
+		o.ArrayLengthPolicy = policy
+	}
+}
+
+// MergeMode 配置转换到已有目标（如ConvertTo）时源字段的写入策略，用于部分更新，
+// 对结构体字段、map的值（合并到目标map已有的key上）及嵌套结构体逐层生效。
+//
+// 支持的策略:
+// - MergeModeOverwrite: 覆盖所有匹配的字段（默认）。
+// - MergeModeSkipZero: 源值为零值时不覆盖目标，指针非nil时即使指向零值也会覆盖。
+// - MergeModeSkipNil: 仅源值为nil（指针、切片、map、接口等）时不覆盖目标。
+func MergeMode(mode internal.MergeMode) Option {
+	return func(o *internal.StructOption) {
+		o.MergeMode = mode
+	}
+}
+
+// SliceStrategy 配置转换到已有的目标切片时的合并策略，可与MergeMode配合使用。
+//
+// 支持的策略:
+// - SliceStrategyReplace: 替换，目标长度与源一致（默认）。
+// - SliceStrategyAppend: 源元素转换后追加到目标末尾。
+// - SliceStrategyIndex: 按下标合并到目标已有元素上，超出部分追加，目标多出的元素保留。
+// - SliceStrategyKey: 按键字段合并，需通过SliceMergeKey指定键字段。
+func SliceStrategy(strategy internal.SliceStrategy) Option {
+	return func(o *internal.StructOption) {
+		o.SliceStrategy = strategy
+	}
+}
+
+// SliceMergeKey 按键字段（元素结构体的字段名，如ID）合并切片：源元素合并到目标中键相同的元素上，未匹配的追加，
+// 源元素键为零值时视为新元素；源或目标元素不是结构体或没有该字段时按SliceStrategyReplace处理
+func SliceMergeKey(field string) Option {
+	return func(o *internal.StructOption) {
+		o.SliceStrategy = internal.SliceStrategyKey
+		o.SliceKey = field
+	}
+}
+
+// EnumTrimPrefix proto枚举转字符串时去掉枚举值名的前缀，需开启ConvProto。
+// 不指定前缀时按枚举类型名推导（如OrderStatus的前缀为ORDER_STATUS_），指定多个时去掉第一个匹配的；
+// 字符串转枚举时始终同时接受完整的名字和去掉前缀后的名字
+func EnumTrimPrefix(prefixes ...string) Option {
+	return func(o *internal.StructOption) {
+		o.EnumTrimPrefix = true
+		o.EnumPrefixes = append(o.EnumPrefixes, prefixes...)
+	}
+}
+
+// EnumCaseInsensitive 字符串转proto枚举时忽略大小写，需开启ConvProto
+func EnumCaseInsensitive() Option {
+	return func(o *internal.StructOption) {
+		o.EnumCaseInsensitive = true
+	}
+}
+
+// EnumUnknownPolicy 配置proto枚举与字符串互转时遇到未定义的值或名字的处理策略，需开启ConvProto。
+//
+// 支持的策略:
+// - EnumUnknownKeep: 枚举转字符串时输出数字，字符串是数字时按数字转为枚举，否则不写入（默认），开启ReportErrors时报错。
+// - EnumUnknownIgnore: 不写入目标。
+// - EnumUnknownError: 不写入目标并报错（同时开启ReportErrors）。
+func EnumUnknownPolicy(policy internal.EnumUnknownPolicy) Option {
+	return func(o *internal.StructOption) {
+		o.EnumUnknownPolicy = policy
+		if policy == internal.EnumUnknownError {
+			o.ReportErrors = true
+		}
+	}
+}
+
+// FieldMask 只转换FieldMask中的字段，同FieldMaskPaths
+func FieldMask(mask *fieldmaskpb.FieldMask) Option {
+	return FieldMaskPaths(mask.GetPaths()...)
+}
+
+// FieldMaskPaths 只转换指定路径的字段，路径按点分隔、生效于源类型，
+// 匹配时忽略大小写和下划线，snake_case（proto字段名）、lowerCamel（json_name）、UpperCamel均可，
+// 没有下级路径的字段整体转换，嵌套消息、repeated字段和map的值按下级路径限制，没有路径时不限制
+func FieldMaskPaths(paths ...string) Option {
+	return func(o *internal.StructOption) {
+		for _, path := range paths {
+			o.FieldMaskFields.Add(internal.FieldMaskName(path))
+		}
+	}
+}
+
+// PackAny From转为*anypb.Any时先按字段转换为消息Msg再打包，如PackAny[*pb.Event, EventDTO]()，
+// 消息与Any直接互转需开启ConvProto
+func PackAny[Msg proto.Message, From any]() Option {
+	return CustomConverterV2(convextend.PackAny[Msg, From]())
+}
+
+// UnpackAny *anypb.Any转为Go结构体To时按type_url解包后按字段转换，如UnpackAny[EventDTO]()，目标为proto消息时无需指定
+func UnpackAny[To any]() Option {
+	return CustomConverterV2(convextend.UnpackAny[To]())
+}
+
+// AnyResolver 解包Any时按type_url查找消息类型的注册表，默认protoregistry.GlobalTypes，需开启ConvProto
+func AnyResolver(resolver protoregistry.MessageTypeResolver) Option {
+	return func(o *internal.StructOption) {
+		o.AnyResolver = resolver
+	}
+}
+
+// CyclePolicy 配置源数据中指针成环（如 A->B->A）及多处引用同一指针时的处理策略，开启后记录已转换的源指针。
+//
+// 支持的策略:
+// - CyclePolicyNone: 不检测（默认），数据成环时会无限递归。
+// - CyclePolicyReuse: 同一源指针只转换一次，目标中成环和共享引用的结构与源一致。
+// - CyclePolicyError: 共享引用同CyclePolicyReuse，成环时报错（同时开启ReportErrors）并将目标指针置为nil。
+func CyclePolicy(policy internal.CyclePolicy) Option {
+	return func(o *internal.StructOption) {
+		o.CyclePolicy = policy
+		if policy == internal.CyclePolicyError {
+			o.ReportErrors = true
+		}
+	}
+}
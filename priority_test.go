@@ -0,0 +1,125 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"github.com/smgrushb/conv/option"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func upperString(s string) (string, error) {
+	return strings.ToUpper(s), nil
+}
+
+func lowerString(s string) (string, error) {
+	return strings.ToLower(s), nil
+}
+
+func shortOnly(s string) (string, error) {
+	if len(s) > 3 {
+		return "", ErrFallthrough
+	}
+	return "<" + s + ">", nil
+}
+
+// exclaimConverter 实现CustomConverter（V1）
+type exclaimConverter struct{}
+
+func (exclaimConverter) Is(dstTyp, srcTyp reflect.Type) bool {
+	return dstTyp.Kind() == reflect.String && srcTyp.Kind() == reflect.String
+}
+
+func (exclaimConverter) Converter() func(dPtr, sPtr unsafe.Pointer) {
+	return func(dPtr, sPtr unsafe.Pointer) {
+		*(*string)(dPtr) = *(*string)(sPtr) + "!"
+	}
+}
+
+func (exclaimConverter) Key() string {
+	return "[exclaim]"
+}
+
+// 优先级高的生效，同优先级CustomConverterV2优先于CustomConverter，再按注册顺序
+func TestConverterPriority(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []option.Option
+		want string
+	}{
+		{"registerOrder", []option.Option{option.Func(upperString), option.Func(lowerString)}, "ABC"},
+		{"higherPriority", []option.Option{option.Func(upperString), option.PriorityConverter(10, option.FuncConverter(lowerString))}, "abc"},
+		{"negativePriority", []option.Option{option.PriorityConverter(-1, option.FuncConverter(upperString)), option.Func(lowerString)}, "abc"},
+		{"v2BeforeV1", []option.Option{option.CustomConverter(exclaimConverter{}), option.Func(upperString)}, "ABC"},
+		{"v1HigherPriority", []option.Option{option.CustomConverter(&priorityExclaim{}), option.Func(upperString)}, "aBc!"},
+	}
+	for _, c := range cases {
+		got, err := Convert[string]("aBc", c.opts...)
+		if err != nil || got != c.want {
+			t.Fatalf("%s: want %q, got %q, %v", c.name, c.want, got, err)
+		}
+	}
+	info, err := Inspect[string, string](option.Func(upperString), option.PriorityConverter(10, option.FuncConverter(lowerString)))
+	if err != nil || info.Kind != "custom" || info.Priority != 10 || !strings.Contains(info.Custom, "func:string<-string") {
+		t.Fatalf("inspect: got %v, %v", info, err)
+	}
+}
+
+// priorityExclaim 通过实现Priority指定优先级
+type priorityExclaim struct {
+	exclaimConverter
+}
+
+func (*priorityExclaim) Priority() int {
+	return 1
+}
+
+func (*priorityExclaim) Key() string {
+	return "[priorityExclaim]"
+}
+
+type fallDst struct {
+	Code string
+	Name string
+}
+
+type fallSrc struct {
+	Code string
+	Name string
+}
+
+// 返回ErrFallthrough时交由内置转换器处理，字段级转换器放行时交由按类型匹配的转换器
+func TestConverterFallthrough(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		opts []option.Option
+		want string
+	}{
+		{"handled", "ab", []option.Option{option.Func(shortOnly)}, "<ab>"},
+		{"builtin", "abcd", []option.Option{option.Func(shortOnly)}, "abcd"},
+		{"lowerPriority", "abcd", []option.Option{option.Func(shortOnly), option.PriorityConverter(-1, option.FuncConverter(upperString))}, "abcd"},
+	}
+	for _, c := range cases {
+		got, err := Convert[string](c.src, c.opts...)
+		if err != nil || got != c.want {
+			t.Fatalf("%s: want %q, got %q, %v", c.name, c.want, got, err)
+		}
+	}
+
+	got, err := Convert[fallDst](fallSrc{Code: "abcd", Name: "xy"},
+		option.FieldConverter("Code", option.FuncConverter(shortOnly)), option.Func(upperString))
+	if err != nil || got != (fallDst{Code: "ABCD", Name: "XY"}) {
+		t.Fatalf("field: got %+v, %v", got, err)
+	}
+	got, err = Convert[fallDst](fallSrc{Code: "ab", Name: "xy"},
+		option.FieldConverter("Code", option.FuncConverter(shortOnly)), option.Func(upperString))
+	if err != nil || got != (fallDst{Code: "<ab>", Name: "XY"}) {
+		t.Fatalf("field handled: got %+v, %v", got, err)
+	}
+}
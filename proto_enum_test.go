@@ -0,0 +1,49 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"errors"
+	"github.com/smgrushb/conv/extend"
+	"github.com/smgrushb/conv/option"
+	"google.golang.org/protobuf/types/known/typepb"
+	"testing"
+)
+
+type enumSrc struct {
+	S string
+}
+
+type enumDst struct {
+	S typepb.Syntax
+}
+
+// EnumUnknownKeep下按数字解析，非数字的名字开启ReportErrors时报错
+func TestString2EnumKeep(t *testing.T) {
+	cases := []struct {
+		name string
+		want typepb.Syntax
+		err  error
+	}{
+		{"PROTO3", typepb.Syntax_SYNTAX_PROTO3, nil},
+		{"SYNTAX_EDITIONS", typepb.Syntax_SYNTAX_EDITIONS, nil},
+		{"7", 7, nil},
+		{"bogus", 0, convextend.ErrUnknownEnum},
+		{"9999999999", 0, ErrOutOfRange},
+	}
+	for _, c := range cases {
+		got, err := Convert[enumDst](enumSrc{S: c.name}, option.ConvProto(), option.ReportErrors())
+		if c.err == nil && err != nil || c.err != nil && !errors.Is(err, c.err) {
+			t.Fatalf("%s: want error %v, got %v", c.name, c.err, err)
+		}
+		if got.S != c.want {
+			t.Fatalf("%s: want %v, got %v", c.name, c.want, got.S)
+		}
+	}
+	if got, err := Convert[enumDst](enumSrc{S: "bogus"}, option.ConvProto()); err != nil || got.S != 0 {
+		t.Fatalf("without ReportErrors: got %v, %v", got.S, err)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"github.com/smgrushb/conv/constant"
+	"github.com/smgrushb/conv/option"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/typepb"
+	"reflect"
+	"testing"
+)
+
+type descOptions struct {
+	Packed     *bool
+	Deprecated bool
+}
+
+type descField struct {
+	Name     *string
+	Number   int64
+	Label    *int32
+	JsonName string
+	Options  *descOptions
+}
+
+// 单个值直接读写：有presence的标量、枚举、嵌套消息，未设置的字段不转换，nil指针清空字段
+func TestProtoFieldMapping(t *testing.T) {
+	label := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	full := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("id"),
+		Number:   proto.Int32(3),
+		Label:    &label,
+		JsonName: proto.String("Id"),
+		Options:  &descriptorpb.FieldOptions{Packed: proto.Bool(true), Deprecated: proto.Bool(true)},
+	}
+	// 非指针的字段写回时总有presence
+	zero := func(m *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+		m.Number, m.JsonName = proto.Int32(m.GetNumber()), proto.String(m.GetJsonName())
+		return m
+	}
+	cases := []struct {
+		name string
+		src  *descriptorpb.FieldDescriptorProto
+		want descField
+		back *descriptorpb.FieldDescriptorProto
+	}{
+		{"full", full, descField{Name: proto.String("id"), Number: 3, Label: proto.Int32(3), JsonName: "Id", Options: &descOptions{Packed: proto.Bool(true), Deprecated: true}}, full},
+		{"unset", &descriptorpb.FieldDescriptorProto{}, descField{}, zero(&descriptorpb.FieldDescriptorProto{})},
+		{"explicitFalse", &descriptorpb.FieldDescriptorProto{Options: &descriptorpb.FieldOptions{Deprecated: proto.Bool(false)}}, descField{Options: &descOptions{}},
+			zero(&descriptorpb.FieldDescriptorProto{Options: &descriptorpb.FieldOptions{Deprecated: proto.Bool(false)}})},
+		{"emptyOptions", &descriptorpb.FieldDescriptorProto{Options: &descriptorpb.FieldOptions{}}, descField{}, zero(&descriptorpb.FieldDescriptorProto{})},
+	}
+	for _, c := range cases {
+		got, err := Convert[descField](c.src)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !equalDescField(got, c.want) {
+			t.Fatalf("%s: want %+v, got %+v", c.name, c.want, got)
+		}
+		back, err := Convert[*descriptorpb.FieldDescriptorProto](got)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !proto.Equal(back, c.back) {
+			t.Fatalf("%s: round trip want %v, got %v", c.name, c.back, back)
+		}
+	}
+
+	dst := proto.Clone(full).(*descriptorpb.FieldDescriptorProto)
+	if err := ConvertTo(descField{Number: 5}, dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Name != nil || dst.Label != nil || dst.Options != nil || dst.GetNumber() != 5 || dst.GetJsonName() != "" {
+		t.Fatalf("nil pointers should clear fields, got %v", dst)
+	}
+}
+
+func equalDescField(a, b descField) bool {
+	eqStr := func(x, y *string) bool { return x == nil && y == nil || x != nil && y != nil && *x == *y }
+	eqInt := func(x, y *int32) bool { return x == nil && y == nil || x != nil && y != nil && *x == *y }
+	eqBool := func(x, y *bool) bool { return x == nil && y == nil || x != nil && y != nil && *x == *y }
+	if !eqStr(a.Name, b.Name) || a.Number != b.Number || !eqInt(a.Label, b.Label) || a.JsonName != b.JsonName {
+		return false
+	}
+	if a.Options == nil || b.Options == nil {
+		return a.Options == b.Options
+	}
+	return eqBool(a.Options.Packed, b.Options.Packed) && a.Options.Deprecated == b.Options.Deprecated
+}
+
+// 同类型按位复制共享嵌套的值，开启DeepCopy时重新分配
+func TestProtoSameType(t *testing.T) {
+	src := &typepb.Field{Name: "id", Options: []*typepb.Option{{Name: "o"}}}
+	cases := []struct {
+		name   string
+		opts   []option.Option
+		shared bool
+	}{
+		{"shallow", nil, true},
+		{"deep", []option.Option{option.DeepCopy()}, false},
+	}
+	for _, c := range cases {
+		got, err := Convert[*typepb.Field](src, c.opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !proto.Equal(got, src) {
+			t.Fatalf("%s: want %v, got %v", c.name, src, got)
+		}
+		if shared := got.Options[0] == src.Options[0]; shared != c.shared {
+			t.Fatalf("%s: want shared %v, got %v", c.name, c.shared, shared)
+		}
+	}
+}
+
+type typeFieldDTO struct {
+	Name   string
+	Number int
+}
+
+type typeCtxDTO struct {
+	FileName string `json:"fileName"`
+}
+
+type typeDTO struct {
+	Name          string
+	Fields        []typeFieldDTO
+	Oneofs        []string
+	SourceContext *typeCtxDTO `json:"sourceContext"`
+	Syntax        string
+}
+
+// 重复字段、嵌套消息、枚举按字段名互转，不同消息类型之间同名字段互转
+func TestProtoListAndMessage(t *testing.T) {
+	src := &typepb.Type{
+		Name:          "T",
+		Fields:        []*typepb.Field{{Name: "a", Number: 1}, {Name: "b", Number: 2}},
+		Oneofs:        []string{"o"},
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "t.proto"},
+		Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+	}
+	dto, err := Convert[typeDTO](src, option.ConvProto())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := typeDTO{Name: "T", Fields: []typeFieldDTO{{"a", 1}, {"b", 2}}, Oneofs: []string{"o"}, SourceContext: &typeCtxDTO{FileName: "t.proto"}, Syntax: "SYNTAX_PROTO3"}
+	if !reflect.DeepEqual(dto, want) {
+		t.Fatalf("want %+v, got %+v", want, dto)
+	}
+	back, err := Convert[*typepb.Type](dto, option.ConvProto())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(back, src) {
+		t.Fatalf("round trip: want %v, got %v", src, back)
+	}
+
+	// 追加到已有的重复字段
+	dst := &typepb.Type{Oneofs: []string{"x"}}
+	if err = ConvertTo(typeDTO{Oneofs: []string{"y"}}, dst, option.SliceStrategy(constant.SliceStrategyAppend)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"x", "y"}; !reflect.DeepEqual(dst.Oneofs, want) {
+		t.Fatalf("append: want %v, got %v", want, dst.Oneofs)
+	}
+
+	api, err := Convert[*apipb.Api](src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantApi := (&apipb.Api{Name: "T", SourceContext: src.SourceContext, Syntax: src.Syntax}); !proto.Equal(api, wantApi) {
+		t.Fatalf("message: want %v, got %v", wantApi, api)
+	}
+}
+
+type structFieldsDTO struct {
+	Fields map[string]flatValue
+}
+
+// map字段的值为消息时按字段转换
+func TestProtoMapField(t *testing.T) {
+	src := &structpb.Struct{Fields: map[string]*structpb.Value{"a": structpb.NewStringValue("x"), "b": structpb.NewNumberValue(2)}}
+	got, err := Convert[structFieldsDTO](src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]flatValue{"a": {StringValue: "x"}, "b": {NumberValue: 2}}
+	if !reflect.DeepEqual(got.Fields, want) {
+		t.Fatalf("want %+v, got %+v", want, got.Fields)
+	}
+	back, err := Convert[*structpb.Struct](got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(back, src) {
+		t.Fatalf("round trip: want %v, got %v", src, back)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"github.com/smgrushb/conv/constant"
+	"github.com/smgrushb/conv/option"
+	"reflect"
+	"testing"
+)
+
+type sliceItem struct {
+	ID   int32
+	Name string
+	Qty  int
+}
+
+type sliceItemDTO struct {
+	ID   int64
+	Name string
+	Qty  int
+}
+
+// 替换、追加、按下标、按键合并到已有的目标切片
+func TestSliceStrategy(t *testing.T) {
+	src := []sliceItem{{ID: 2, Qty: 5}, {ID: 3, Name: "c"}, {Name: "new"}}
+	cases := []struct {
+		name string
+		opts []option.Option
+		want []sliceItemDTO
+	}{
+		{"replace", nil, []sliceItemDTO{{ID: 2, Qty: 5}, {ID: 3, Name: "c"}, {Name: "new"}}},
+		{"append", []option.Option{option.SliceStrategy(constant.SliceStrategyAppend)},
+			[]sliceItemDTO{{ID: 1, Name: "a", Qty: 1}, {ID: 2, Name: "b", Qty: 2}, {ID: 2, Qty: 5}, {ID: 3, Name: "c"}, {Name: "new"}}},
+		{"index", []option.Option{option.SliceStrategy(constant.SliceStrategyIndex)},
+			[]sliceItemDTO{{ID: 2, Qty: 5}, {ID: 3, Name: "c"}, {Name: "new"}}},
+		{"indexSkipZero", []option.Option{option.SliceStrategy(constant.SliceStrategyIndex), option.MergeMode(constant.MergeModeSkipZero)},
+			[]sliceItemDTO{{ID: 2, Name: "a", Qty: 5}, {ID: 3, Name: "c", Qty: 2}, {Name: "new"}}},
+		{"key", []option.Option{option.SliceMergeKey("ID")},
+			[]sliceItemDTO{{ID: 1, Name: "a", Qty: 1}, {ID: 2, Qty: 5}, {ID: 3, Name: "c"}, {Name: "new"}}},
+		{"keySkipZero", []option.Option{option.SliceMergeKey("ID"), option.MergeMode(constant.MergeModeSkipZero)},
+			[]sliceItemDTO{{ID: 1, Name: "a", Qty: 1}, {ID: 2, Name: "b", Qty: 5}, {ID: 3, Name: "c"}, {Name: "new"}}},
+		{"keyMissingField", []option.Option{option.SliceMergeKey("Missing")}, []sliceItemDTO{{ID: 2, Qty: 5}, {ID: 3, Name: "c"}, {Name: "new"}}},
+	}
+	for _, c := range cases {
+		dst := []sliceItemDTO{{ID: 1, Name: "a", Qty: 1}, {ID: 2, Name: "b", Qty: 2}}
+		if err := ConvertTo(src, &dst, c.opts...); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !reflect.DeepEqual(dst, c.want) {
+			t.Fatalf("%s: want %+v, got %+v", c.name, c.want, dst)
+		}
+	}
+}
+
+// 按下标合并时目标多出的元素保留，按键合并时指针元素合并到原有对象上
+func TestSliceMergeExisting(t *testing.T) {
+	dst := []int{1, 2, 3}
+	if err := ConvertTo([]int8{9}, &dst, option.SliceStrategy(constant.SliceStrategyIndex)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{9, 2, 3}; !reflect.DeepEqual(dst, want) {
+		t.Fatalf("index: want %v, got %v", want, dst)
+	}
+
+	kept := &sliceItemDTO{ID: 7, Name: "kept", Qty: 1}
+	ptrs := []*sliceItemDTO{kept, nil}
+	err := ConvertTo([]*sliceItem{{ID: 7, Qty: 3}, nil}, &ptrs, option.SliceMergeKey("ID"), option.MergeMode(constant.MergeModeSkipZero))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ptrs) != 3 || ptrs[0] != kept || *kept != (sliceItemDTO{ID: 7, Name: "kept", Qty: 3}) || ptrs[1] != nil {
+		t.Fatalf("key: unexpected result %+v", ptrs)
+	}
+}
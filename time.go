@@ -26,6 +26,11 @@ func SetTimeFormat(format string) {
 	internal.TimeFormat = format
 }
 
+// SetTimeLocation 设置全局时区，优先级低于option.TimeLocation和tz标签，不设置时解析使用time.Local，输出保留原时区
+func SetTimeLocation(loc *time.Location) {
+	internal.TimeLocation = loc
+}
+
 func SetMinUnix(unix int64) {
 	internal.MinUnix = &unix
 }
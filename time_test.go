@@ -0,0 +1,185 @@
+// Copyright 2025 smgrushb
+// Licensed under the Apache License, Version 2.0
+// https://www.apache.org/licenses/LICENSE-2.0
+// Inspired by coven (MIT License) by petersunbag
+
+package conv
+
+import (
+	"errors"
+	"github.com/smgrushb/conv/option"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type badUnitDst struct {
+	Name string
+	At   int64 `unit:"min"`
+}
+
+type badTzDst struct {
+	Name string
+	At   string `tz:"Mars/Olympus"`
+}
+
+type timeTagSrc struct {
+	Name string
+	At   time.Time
+}
+
+// 无法识别的unit、tz每次转换都报错并指出tag，该字段不写入，其余字段照常转换
+func TestInvalidTimeTag(t *testing.T) {
+	src := timeTagSrc{Name: "a", At: time.Unix(60, 0)}
+	var unit badUnitDst
+	err := ConvertTo(src, &unit)
+	if !errors.Is(err, ErrInvalidTag) || !strings.Contains(err.Error(), `unit:"min"`) {
+		t.Fatalf("unit: want %v naming the tag, got %v", ErrInvalidTag, err)
+	}
+	if unit.Name != "a" || unit.At != 0 {
+		t.Fatalf("unit: got %+v", unit)
+	}
+	var tz badTzDst
+	err = ConvertTo(src, &tz)
+	if !errors.Is(err, ErrInvalidTag) || !strings.Contains(err.Error(), `tz:"Mars/Olympus"`) {
+		t.Fatalf("tz: want %v naming the tag, got %v", ErrInvalidTag, err)
+	}
+	if tz.Name != "a" || tz.At != "" {
+		t.Fatalf("tz: got %+v", tz)
+	}
+}
+
+type unitTagDst struct {
+	S  int64   `unit:"s"`
+	Ms int64   `unit:"ms"`
+	Us uint64  `unit:"us"`
+	Ns int64   `unit:"ns"`
+	F  float64 `unit:"ms"`
+}
+
+type unitTagSrc struct {
+	S, Ms, Us, Ns, F time.Time
+}
+
+// 字段tag中的unit分别生效，浮点数保留秒以下的部分
+func TestTimeUnitTag(t *testing.T) {
+	at := time.Unix(1640995200, 123456789)
+	got, err := Convert[unitTagDst](unitTagSrc{S: at, Ms: at, Us: at, Ns: at, F: at})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := unitTagDst{S: 1640995200, Ms: 1640995200123, Us: 1640995200123456, Ns: 1640995200123456789, F: 1640995200123.456789}
+	if got != want {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+	back, err := Convert[unitTagSrc](got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, c := range map[string]struct{ got, want time.Time }{
+		"S":  {back.S, time.Unix(1640995200, 0)},
+		"Ms": {back.Ms, time.Unix(1640995200, 123000000)},
+		"Us": {back.Us, time.Unix(1640995200, 123456000)},
+		"Ns": {back.Ns, at},
+	} {
+		if !c.got.Equal(c.want) {
+			t.Fatalf("%s: want %v, got %v", name, c.want, c.got)
+		}
+	}
+	if d := back.F.Sub(at); d < -time.Microsecond || d > time.Microsecond {
+		t.Fatalf("F: want about %v, got %v", at, back.F)
+	}
+}
+
+// 时间与数值互转：单位、零值、超出范围时报错且不写入
+func TestTimeNumber(t *testing.T) {
+	at := time.Unix(1640995200, 0)
+	far := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name string
+		conv func() (any, error)
+		want any
+		err  error
+	}{
+		{"seconds", func() (any, error) { return Convert[int64](at) }, int64(1640995200), nil},
+		{"millis", func() (any, error) { return Convert[int64](at, option.TimeUnit(time.Millisecond)) }, int64(1640995200000), nil},
+		{"minutes", func() (any, error) { return Convert[int64](at, option.TimeUnit(time.Minute)) }, int64(27349920), nil},
+		{"unsupportedUnit", func() (any, error) { return Convert[int64](at, option.TimeUnit(7*time.Millisecond)) }, int64(1640995200), nil},
+		{"zeroTime", func() (any, error) { return Convert[int64](time.Time{}) }, int64(0), nil},
+		{"nanosOverflow", func() (any, error) {
+			return Convert[int64](far, option.TimeUnit(time.Nanosecond), option.ReportErrors())
+		}, int64(0), ErrOutOfRange},
+		{"int32Overflow", func() (any, error) { return Convert[int32](far, option.ReportErrors()) }, int32(0), ErrOutOfRange},
+		{"negativeUint", func() (any, error) { return Convert[uint64](time.Unix(-1, 0), option.ReportErrors()) }, uint64(0), ErrOutOfRange},
+		{"fromSeconds", func() (any, error) { return Convert[time.Time](int64(1640995200)) }, at, nil},
+		{"fromMillis", func() (any, error) { return Convert[time.Time](1640995200500, option.TimeUnit(time.Millisecond)) }, at.Add(500 * time.Millisecond), nil},
+		{"fromNegativeMillis", func() (any, error) { return Convert[time.Time](-1500, option.TimeUnit(time.Millisecond)) }, time.Unix(-1, -5e8), nil},
+		{"fromFloat", func() (any, error) { return Convert[time.Time](1.5) }, time.Unix(1, 5e8), nil},
+		{"fromZero", func() (any, error) { return Convert[time.Time](0) }, time.Time{}, nil},
+		{"fromMinutesOverflow", func() (any, error) {
+			return Convert[time.Time](int64(math.MaxInt64), option.TimeUnit(time.Minute), option.ReportErrors())
+		}, time.Time{}, ErrOutOfRange},
+		{"fromNaN", func() (any, error) { return Convert[time.Time](math.NaN(), option.ReportErrors()) }, time.Time{}, ErrOutOfRange},
+		{"fromUintOverflow", func() (any, error) { return Convert[time.Time](uint64(math.MaxUint64), option.ReportErrors()) }, time.Time{}, ErrOutOfRange},
+	}
+	for _, c := range cases {
+		got, err := c.conv()
+		if c.err == nil && err != nil || c.err != nil && !errors.Is(err, c.err) {
+			t.Fatalf("%s: want error %v, got %v", c.name, c.err, err)
+		}
+		if tm, ok := got.(time.Time); ok {
+			if !tm.Equal(c.want.(time.Time)) {
+				t.Fatalf("%s: want %v, got %v", c.name, c.want, tm)
+			}
+		} else if got != c.want {
+			t.Fatalf("%s: want %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+type layoutTagDst struct {
+	At time.Time `format:"2006/01/02|auto"`
+}
+
+// 多个layout依次尝试，auto展开为常见的ISO-8601格式，全部失败时报错
+func TestTimeLayouts(t *testing.T) {
+	utc := option.TimeLocation(time.UTC)
+	day := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		src    string
+		format option.Option
+		want   time.Time
+		err    string
+	}{
+		{"first", "2022/01/02", option.TimeFormats("2006/01/02", "2006-01-02"), day, ""},
+		{"second", "2022-01-02", option.TimeFormats("2006/01/02", "2006-01-02"), day, ""},
+		{"sep", "2022-01-02", option.TimeFormat("2006/01/02|2006-01-02"), day, ""},
+		{"autoRFC3339", "2022-01-02T08:00:00+08:00", option.TimeFormat("auto"), day, ""},
+		{"autoFraction", "2022-01-02T00:00:00.25Z", option.TimeFormat("auto"), day.Add(250 * time.Millisecond), ""},
+		{"autoLocal", "2022-01-02T03:04", option.TimeFormat("auto"), day.Add(3*time.Hour + 4*time.Minute), ""},
+		{"autoDate", "2022-01-02", option.TimeFormat("auto"), day, ""},
+		{"autoBasic", "20220102T030405Z", option.TimeFormat("auto"), day.Add(3*time.Hour + 4*time.Minute + 5*time.Second), ""},
+		{"noMatch", "02.01.2022", option.TimeFormats("2006/01/02", "auto"), time.Time{}, "no layout matched"},
+		{"single", "02.01.2022", option.TimeFormat("2006/01/02"), time.Time{}, "cannot parse"},
+	}
+	for _, c := range cases {
+		got, err := Convert[time.Time](c.src, c.format, utc, option.ReportErrors())
+		if len(c.err) == 0 && err != nil || len(c.err) > 0 && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("%s: want error %q, got %v", c.name, c.err, err)
+		}
+		if !got.Equal(c.want) {
+			t.Fatalf("%s: want %v, got %v", c.name, c.want, got)
+		}
+	}
+
+	// tag中的多个layout，格式化时使用第一个
+	got, err := Convert[layoutTagDst](map[string]any{"At": "2022-01-02T00:00:00Z"}, utc)
+	if err != nil || !got.At.Equal(day) {
+		t.Fatalf("tag: got %v, %v", got.At, err)
+	}
+	if s, err := Convert[string](day, option.TimeFormats("2006/01/02", "auto"), utc); err != nil || s != "2022/01/02" {
+		t.Fatalf("format: got %q, %v", s, err)
+	}
+}